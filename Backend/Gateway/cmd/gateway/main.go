@@ -4,15 +4,22 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/raphael-guer1n/AREA/area-gateway/internal/config"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/health"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/jwks"
 	"github.com/raphael-guer1n/AREA/area-gateway/internal/middleware"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/openapi"
 	"github.com/raphael-guer1n/AREA/area-gateway/internal/registry"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/revocation"
 	"github.com/raphael-guer1n/AREA/area-gateway/internal/router"
 )
 
+const servicesConfigDir = "./services-config"
+
 func main() {
-	services, err := config.LoadAllServiceConfigs("./services-config")
+	services, err := config.LoadAllServiceConfigs(servicesConfigDir)
 	if err != nil {
 		log.Fatalf("[FATAL] failed to load service configs: %v", err)
 	}
@@ -21,6 +28,11 @@ func main() {
 		log.Fatalf("[FATAL] invalid service configuration: %v", err)
 	}
 
+	docs := openapi.NewAggregator()
+	if err := docs.Build(services); err != nil {
+		log.Fatalf("[FATAL] invalid OpenAPI specs: %v", err)
+	}
+
 	log.Println("[INFO] All service configs are valid.")
 
 	reg := registry.NewRegistry()
@@ -40,10 +52,49 @@ func main() {
 
 	log.Printf("[INFO] Gateway starting on port %d (debug=%v)", cfg.Port, cfg.DebugMode)
 
-	authMW := middleware.NewAuthMiddleware(cfg, reg)
+	var keySet *jwks.KeySet
+	if cfg.JwksURL != "" {
+		keySet = jwks.NewKeySet(
+			cfg.JwksURL,
+			time.Duration(cfg.JwksRefreshIntervalMs)*time.Millisecond,
+			time.Duration(cfg.JwksMinRefreshIntervalMs)*time.Millisecond,
+			time.Duration(cfg.JwksKeyGraceMs)*time.Millisecond,
+		)
+		keySet.Start()
+		log.Printf("[INFO] Verifying JWTs against JWKS at %s", cfg.JwksURL)
+	}
+
+	var apiKeyVerifier middleware.APIKeyVerifier
+	if cfg.ApiKeyVerifyURL != "" {
+		apiKeyVerifier = middleware.NewHTTPAPIKeyVerifier(
+			cfg.ApiKeyVerifyURL,
+			cfg.InternalSecret,
+			time.Duration(cfg.ApiKeyCacheTtlMs)*time.Millisecond,
+		)
+	}
+
+	var denyList *revocation.DenyList
+	if cfg.TokenRevocationURL != "" {
+		denyList = revocation.NewDenyList(
+			cfg.TokenRevocationURL,
+			cfg.InternalSecret,
+			time.Duration(cfg.TokenRevocationSyncMs)*time.Millisecond,
+		)
+		denyList.Start()
+		log.Printf("[INFO] Syncing revoked tokens from %s", cfg.TokenRevocationURL)
+	}
+
+	authMW := middleware.NewAuthMiddleware(cfg, reg, keySet, apiKeyVerifier, denyList)
 	permMW := middleware.NewPermissionsMiddleware(reg)
 	internalMW := middleware.NewInternalMiddleware(cfg, reg)
 	loggingMW := middleware.NewLoggingMiddleware(reg)
+	rateLimitMW := middleware.NewRateLimitMiddleware(reg, middleware.NewMemoryRateLimitStore())
+	bodyMW := middleware.NewBodyMiddleware(cfg, reg)
+	metricsMW := middleware.NewMetricsMiddleware()
+	identityMW := middleware.NewIdentityMiddleware(cfg)
+
+	checker := health.NewChecker(cfg, reg)
+	checker.Start()
 
 	rt := router.NewRouter(
 		reg,
@@ -52,6 +103,12 @@ func main() {
 		permMW,
 		internalMW,
 		loggingMW,
+		rateLimitMW,
+		bodyMW,
+		metricsMW,
+		identityMW,
+		checker,
+		docs,
 	)
 
 	mux, err := rt.Build()
@@ -59,6 +116,15 @@ func main() {
 		log.Fatalf("[FATAL] failed to build router: %v", err)
 	}
 
+	reloader := registry.NewReloader(
+		servicesConfigDir,
+		reg,
+		time.Duration(cfg.ConfigWatchIntervalMs)*time.Millisecond,
+	)
+	reloader.Stage(docs.Prepare)
+	reloader.OnReload(rt.SyncProxies)
+	reloader.Start()
+
 	corsMW := middleware.NewCORSMiddleware(cfg)
 	handler := corsMW.Handler(mux)
 
@@ -5,3 +5,5 @@ go 1.24.10
 require github.com/joho/godotenv v1.5.1
 
 require github.com/golang-jwt/jwt/v5 v5.3.0
+
+require gopkg.in/yaml.v3 v3.0.1
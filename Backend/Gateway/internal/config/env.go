@@ -18,6 +18,42 @@ type GatewayConfig struct {
 	JwtAlgorithm     string
 	JwtSecret        string
 	AllowedOrigins   []string
+
+	JwksURL                  string
+	JwksRefreshIntervalMs    int
+	JwksMinRefreshIntervalMs int
+	JwksKeyGraceMs           int
+
+	ApiKeyVerifyURL  string
+	ApiKeyCacheTtlMs int
+
+	TokenRevocationURL    string
+	TokenRevocationSyncMs int
+
+	ConfigWatchIntervalMs int
+
+	HealthCheckIntervalMs   int
+	HealthCheckTimeoutMs    int
+	CircuitFailureThreshold int
+	CircuitOpenMs           int
+
+	UpstreamEjectThreshold int
+	UpstreamEjectMs        int
+
+	MaxBodyBytes        int
+	AllowedContentTypes []string
+}
+
+func getIntEnv(name string, def int) (int, error) {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return v, nil
 }
 
 func LoadGatewayConfig() (*GatewayConfig, error) {
@@ -38,9 +74,11 @@ func LoadGatewayConfig() (*GatewayConfig, error) {
 		return nil, fmt.Errorf("missing required env INTERNAL_SECRET")
 	}
 
+	cfg.JwksURL = os.Getenv("JWT_JWKS_URL")
+
 	cfg.JwtPublicKey = os.Getenv("JWT_PUBLIC_KEY")
-	if cfg.JwtPublicKey == "" {
-		return nil, fmt.Errorf("missing required env JWT_PUBLIC_KEY")
+	if cfg.JwtPublicKey == "" && cfg.JwksURL == "" {
+		return nil, fmt.Errorf("missing required env JWT_PUBLIC_KEY (or JWT_JWKS_URL)")
 	}
 
 	cfg.JwtPrivateKey = os.Getenv("JWT_PRIVATE_KEY")
@@ -74,6 +112,69 @@ func LoadGatewayConfig() (*GatewayConfig, error) {
 	    return nil, fmt.Errorf("invalid JWT_ALGO: must be RS256 or HS256")
 	}
 
+	if cfg.JwksRefreshIntervalMs, err = getIntEnv("JWT_JWKS_REFRESH_INTERVAL_MS", 300000); err != nil {
+		return nil, err
+	}
+	if cfg.JwksMinRefreshIntervalMs, err = getIntEnv("JWT_JWKS_MIN_REFRESH_INTERVAL_MS", 30000); err != nil {
+		return nil, err
+	}
+	if cfg.JwksKeyGraceMs, err = getIntEnv("JWT_JWKS_KEY_GRACE_MS", 86400000); err != nil {
+		return nil, err
+	}
+
+	cfg.ApiKeyVerifyURL = os.Getenv("API_KEY_VERIFY_URL")
+	if cfg.ApiKeyCacheTtlMs, err = getIntEnv("API_KEY_CACHE_TTL_MS", 30000); err != nil {
+		return nil, err
+	}
+
+	cfg.TokenRevocationURL = os.Getenv("TOKEN_REVOCATION_URL")
+	if cfg.TokenRevocationSyncMs, err = getIntEnv("TOKEN_REVOCATION_SYNC_MS", 5000); err != nil {
+		return nil, err
+	}
+
+	if cfg.ConfigWatchIntervalMs, err = getIntEnv("CONFIG_WATCH_INTERVAL_MS", 5000); err != nil {
+		return nil, err
+	}
+
+	if cfg.HealthCheckIntervalMs, err = getIntEnv("HEALTH_CHECK_INTERVAL_MS", 10000); err != nil {
+		return nil, err
+	}
+	if cfg.HealthCheckTimeoutMs, err = getIntEnv("HEALTH_CHECK_TIMEOUT_MS", 2000); err != nil {
+		return nil, err
+	}
+	if cfg.CircuitFailureThreshold, err = getIntEnv("CIRCUIT_FAILURE_THRESHOLD", 5); err != nil {
+		return nil, err
+	}
+	if cfg.CircuitOpenMs, err = getIntEnv("CIRCUIT_OPEN_MS", 30000); err != nil {
+		return nil, err
+	}
+
+	if cfg.UpstreamEjectThreshold, err = getIntEnv("UPSTREAM_EJECT_THRESHOLD", 3); err != nil {
+		return nil, err
+	}
+	if cfg.UpstreamEjectMs, err = getIntEnv("UPSTREAM_EJECT_MS", 30000); err != nil {
+		return nil, err
+	}
+
+	if cfg.MaxBodyBytes, err = getIntEnv("MAX_BODY_BYTES", 10<<20); err != nil {
+		return nil, err
+	}
+	if cfg.MaxBodyBytes < 0 {
+		return nil, fmt.Errorf("invalid MAX_BODY_BYTES: cannot be negative")
+	}
+	if types := os.Getenv("ALLOWED_CONTENT_TYPES"); types != "" {
+		for _, ct := range strings.Split(types, ",") {
+			ct = strings.ToLower(strings.TrimSpace(ct))
+			if ct == "" {
+				continue
+			}
+			if err := ValidateContentType(ct); err != nil {
+				return nil, fmt.Errorf("invalid ALLOWED_CONTENT_TYPES: %w", err)
+			}
+			cfg.AllowedContentTypes = append(cfg.AllowedContentTypes, ct)
+		}
+	}
+
 	origins := os.Getenv("ALLOWED_ORIGINS")
 	if origins != "" {
 		for _, o := range strings.Split(origins, ",") {
@@ -45,5 +45,9 @@ func loadSingleConfig(path string) (ServiceConfig, error) {
 		return cfg, fmt.Errorf("failed to parse JSON: %w", err)
 	}
 
+	if cfg.OpenAPI != "" && !filepath.IsAbs(cfg.OpenAPI) {
+		cfg.OpenAPI = filepath.Join(filepath.Dir(path), cfg.OpenAPI)
+	}
+
 	return cfg, nil
 }
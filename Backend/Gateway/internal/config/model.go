@@ -1,9 +1,32 @@
 package config
 
+const (
+	LoadBalancingRoundRobin       = "round_robin"
+	LoadBalancingLeastConnections = "least_connections"
+)
+
 type ServiceConfig struct {
-	Name    string        `json:"name"`
-	BaseURL string        `json:"base_url"`
-	Routes  []RouteConfig `json:"routes"`
+	Name          string        `json:"name"`
+	BaseURL       string        `json:"base_url"`
+	BaseURLs      []string      `json:"base_urls,omitempty"`
+	LoadBalancing string        `json:"load_balancing,omitempty"`
+	Routes        []RouteConfig `json:"routes"`
+
+	// OpenAPI is the path of the service spec, relative to its
+	// service.config.json. The loader resolves it.
+	OpenAPI string `json:"openapi,omitempty"`
+}
+
+// Backends returns every upstream instance of the service, whether it was
+// declared with `base_url` or `base_urls`.
+func (s ServiceConfig) Backends() []string {
+	if len(s.BaseURLs) > 0 {
+		return s.BaseURLs
+	}
+	if s.BaseURL == "" {
+		return nil
+	}
+	return []string{s.BaseURL}
 }
 
 type RouteConfig struct {
@@ -13,9 +36,24 @@ type RouteConfig struct {
 	AuthRequired bool     `json:"auth_required"`
 	Permissions  []string `json:"permissions,omitempty"`
 
+	// Scopes lists the API key scopes accepted on the route; a key needs one
+	// of them. API keys are refused on routes without scopes.
+	Scopes []string `json:"scopes,omitempty"`
+
 	InternalOnly bool `json:"internal_only"`
 
 	RateLimit *RateLimitConfig `json:"rate_limit,omitempty"`
+
+	TimeoutMs int `json:"timeout_ms,omitempty"`
+
+	// Streaming routes may upgrade to WebSocket or stream (SSE) and are not
+	// bound by the request timeout.
+	Streaming bool `json:"streaming,omitempty"`
+
+	// MaxBodyBytes and AllowedContentTypes override the gateway-wide
+	// MAX_BODY_BYTES and ALLOWED_CONTENT_TYPES for the route.
+	MaxBodyBytes        int      `json:"max_body_bytes,omitempty"`
+	AllowedContentTypes []string `json:"allowed_content_types,omitempty"`
 }
 
 type RateLimitConfig struct {
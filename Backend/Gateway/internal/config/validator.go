@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 )
 
@@ -17,10 +18,66 @@ var validHTTPMethods = map[string]bool{
 }
 
 func ValidateAll(services []ServiceConfig) error {
+	names := make(map[string]struct{}, len(services))
 	for _, svc := range services {
 		if err := validateServiceConfig(svc); err != nil {
 			return fmt.Errorf("service '%s' invalid: %w", svc.Name, err)
 		}
+		if _, exists := names[svc.Name]; exists {
+			return fmt.Errorf("duplicate service name '%s'", svc.Name)
+		}
+		names[svc.Name] = struct{}{}
+	}
+	return validateAmbiguousRoutes(services)
+}
+
+// RouteShape normalizes a path pattern so that routes matching exactly the
+// same requests compare equal: parameter names are dropped.
+func RouteShape(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, seg := range segments {
+		switch {
+		case isWildcardSegment(seg):
+			segments[i] = "{...}"
+		case isParamSegment(seg):
+			segments[i] = "{}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+func isParamSegment(seg string) bool {
+	return strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") && len(seg) > 2
+}
+
+func isWildcardSegment(seg string) bool {
+	return isParamSegment(seg) && strings.HasSuffix(seg, "...}")
+}
+
+// validateAmbiguousRoutes rejects a direct path that would shadow the
+// namespaced path of another service. Direct paths shared by several services
+// are not an error: the registry only exposes them under their prefix.
+func validateAmbiguousRoutes(services []ServiceConfig) error {
+	namespaced := make(map[string]string)
+	for _, svc := range services {
+		for _, r := range svc.Routes {
+			for _, m := range r.Methods {
+				namespaced[m+" "+RouteShape("/"+svc.Name+r.Path)] = svc.Name
+			}
+		}
+	}
+
+	for _, svc := range services {
+		for _, r := range svc.Routes {
+			for _, m := range r.Methods {
+				if owner, ok := namespaced[m+" "+RouteShape(r.Path)]; ok {
+					return fmt.Errorf(
+						"ambiguous route: %s %s of service '%s' collides with the namespaced routes of service '%s'",
+						m, r.Path, svc.Name, owner,
+					)
+				}
+			}
+		}
 	}
 	return nil
 }
@@ -29,9 +86,23 @@ func validateServiceConfig(svc ServiceConfig) error {
 	if svc.Name == "" {
 		return errors.New("missing service name")
 	}
-	if svc.BaseURL == "" {
+	if svc.BaseURL != "" && len(svc.BaseURLs) > 0 {
+		return errors.New("use either base_url or base_urls, not both")
+	}
+	if len(svc.Backends()) == 0 {
 		return errors.New("missing base_url")
 	}
+	for _, backend := range svc.Backends() {
+		u, err := url.Parse(backend)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid backend url '%s'", backend)
+		}
+	}
+	switch svc.LoadBalancing {
+	case "", LoadBalancingRoundRobin, LoadBalancingLeastConnections:
+	default:
+		return fmt.Errorf("invalid load_balancing '%s'", svc.LoadBalancing)
+	}
 	if len(svc.Routes) == 0 {
 		return errors.New("service has no routes")
 	}
@@ -44,9 +115,9 @@ func validateServiceConfig(svc ServiceConfig) error {
 		}
 
 		for _, m := range r.Methods {
-			key := fmt.Sprintf("%s#%s", r.Path, m)
+			key := fmt.Sprintf("%s#%s", RouteShape(r.Path), m)
 			if _, exists := routeSet[key]; exists {
-				return fmt.Errorf("duplicate route+method: %s %s", m, r.Path)
+				return fmt.Errorf("duplicate or ambiguous route+method: %s %s", m, r.Path)
 			}
 			routeSet[key] = struct{}{}
 		}
@@ -65,6 +136,15 @@ func validateRouteConfig(r RouteConfig) error {
 	if strings.Contains(r.Path, "//") {
 		return errors.New("path cannot contain double slashes '//'")
 	}
+	segments := strings.Split(strings.Trim(r.Path, "/"), "/")
+	for i, seg := range segments {
+		if strings.ContainsAny(seg, "{}") && !isParamSegment(seg) {
+			return fmt.Errorf("malformed path parameter '%s'", seg)
+		}
+		if isWildcardSegment(seg) && i != len(segments)-1 {
+			return errors.New("wildcard '{name...}' must be the last path segment")
+		}
+	}
 
 	if len(r.Methods) == 0 {
 		return errors.New("route must declare at least one HTTP method")
@@ -80,9 +160,53 @@ func validateRouteConfig(r RouteConfig) error {
 		// TO DO
 	}
 
+	if len(r.Scopes) > 0 && !r.AuthRequired {
+		return errors.New("scopes require auth_required=true")
+	}
+	for _, scope := range r.Scopes {
+		if scope == "" || strings.ContainsAny(scope, ", ") {
+			return fmt.Errorf("invalid scope '%s'", scope)
+		}
+	}
+
 	if r.InternalOnly && r.AuthRequired {
 		return errors.New("internal_only routes cannot have auth_required=true")
 	}
 
+	if r.TimeoutMs < 0 {
+		return errors.New("timeout_ms cannot be negative")
+	}
+	if r.Streaming && r.TimeoutMs > 0 {
+		return errors.New("timeout_ms does not apply to streaming routes")
+	}
+
+	if r.MaxBodyBytes < 0 {
+		return errors.New("max_body_bytes cannot be negative")
+	}
+	for _, ct := range r.AllowedContentTypes {
+		if err := ValidateContentType(ct); err != nil {
+			return err
+		}
+	}
+
+	if r.RateLimit != nil {
+		if r.RateLimit.Requests <= 0 {
+			return errors.New("rate_limit.requests must be greater than 0")
+		}
+		if r.RateLimit.PerSec <= 0 {
+			return errors.New("rate_limit.per_sec must be greater than 0")
+		}
+	}
+
+	return nil
+}
+
+// ValidateContentType accepts a media type such as `application/json`, or a
+// `type/*` pattern. Parameters are not allowed.
+func ValidateContentType(ct string) error {
+	mediaType, subType, ok := strings.Cut(ct, "/")
+	if !ok || mediaType == "" || subType == "" || mediaType == "*" || strings.ContainsAny(ct, " ;,") {
+		return fmt.Errorf("invalid content type '%s'", ct)
+	}
 	return nil
 }
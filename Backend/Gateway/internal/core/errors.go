@@ -37,4 +37,9 @@ const (
 	ErrNotFound          = "not_found"
 	ErrBadGateway        = "bad_gateway"
 	ErrGatewayTimeout    = "gateway_timeout"
+	ErrRateLimited       = "rate_limited"
+	ErrCircuitOpen       = "circuit_open"
+	ErrPayloadTooLarge   = "payload_too_large"
+	ErrUnsupportedMedia  = "unsupported_media_type"
+	ErrBadRequest        = "bad_request"
 )
@@ -1 +1,21 @@
-package core
\ No newline at end of file
+package core
+
+import (
+	"net"
+	"strings"
+)
+
+func ClientIPFromRemoteAddr(addr string) string {
+	if strings.HasPrefix(addr, "[") {
+		end := strings.Index(addr, "]")
+		if end != -1 {
+			return addr[1:end]
+		}
+	}
+
+	ip, _, err := net.SplitHostPort(addr)
+	if err == nil {
+		return ip
+	}
+	return addr
+}
@@ -0,0 +1,97 @@
+package health
+
+import (
+	"sync"
+	"time"
+)
+
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// Breaker opens after `threshold` consecutive upstream failures and rejects
+// traffic for `cooldown`; it then lets a single trial request through and
+// closes again on the first success.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	state        CircuitState
+	failures     int
+	openedAt     time.Time
+	trialStarted time.Time
+	lastError    string
+	now          func() time.Time
+	mu           sync.Mutex
+}
+
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	if threshold <= 0 {
+		threshold = 1
+	}
+	return &Breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		state:     CircuitClosed,
+		now:       time.Now,
+	}
+}
+
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	switch b.state {
+	case CircuitOpen:
+		if now.Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = CircuitHalfOpen
+		b.trialStarted = now
+		return true
+	case CircuitHalfOpen:
+		if now.Sub(b.trialStarted) < b.cooldown {
+			return false
+		}
+		b.trialStarted = now
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = CircuitClosed
+	b.failures = 0
+	b.lastError = ""
+}
+
+func (b *Breaker) Failure(reason string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.lastError = reason
+
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		if b.state != CircuitOpen {
+			b.openedAt = b.now()
+		}
+		b.state = CircuitOpen
+	}
+}
+
+func (b *Breaker) snapshot() (CircuitState, int, string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state, b.failures, b.lastError
+}
@@ -0,0 +1,128 @@
+package health
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestBreaker(threshold int, cooldown time.Duration) (*Breaker, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	b := NewBreaker(threshold, cooldown)
+	b.now = clock.Now
+	return b, clock
+}
+
+func assertState(t *testing.T, b *Breaker, want CircuitState) {
+	t.Helper()
+	if state, _, _ := b.snapshot(); state != want {
+		t.Fatalf("state = %s, want %s", state, want)
+	}
+}
+
+func TestBreaker_OpensAfterThreshold(t *testing.T) {
+	b, _ := newTestBreaker(3, 10*time.Second)
+
+	b.Failure("timeout")
+	b.Failure("timeout")
+	assertState(t, b, CircuitClosed)
+	if !b.Allow() {
+		t.Fatal("closed breaker refused a request")
+	}
+
+	b.Failure("connection refused")
+	assertState(t, b, CircuitOpen)
+	if b.Allow() {
+		t.Fatal("open breaker let a request through")
+	}
+	if _, failures, lastError := b.snapshot(); failures != 3 || lastError != "connection refused" {
+		t.Fatalf("failures = %d, lastError = %q", failures, lastError)
+	}
+}
+
+func TestBreaker_SuccessResetsFailures(t *testing.T) {
+	b, _ := newTestBreaker(2, 10*time.Second)
+
+	b.Failure("timeout")
+	b.Success()
+	b.Failure("timeout")
+
+	assertState(t, b, CircuitClosed)
+}
+
+func TestBreaker_HalfOpenTrial(t *testing.T) {
+	testCases := []struct {
+		name      string
+		succeeded bool
+		want      CircuitState
+	}{
+		{"trial success closes", true, CircuitClosed},
+		{"trial failure opens again", false, CircuitOpen},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, clock := newTestBreaker(1, 10*time.Second)
+			b.Failure("timeout")
+
+			clock.now = clock.now.Add(9 * time.Second)
+			if b.Allow() {
+				t.Fatal("breaker let a request through during the cooldown")
+			}
+
+			clock.now = clock.now.Add(time.Second)
+			if !b.Allow() {
+				t.Fatal("breaker refused the trial request after the cooldown")
+			}
+			assertState(t, b, CircuitHalfOpen)
+			if b.Allow() {
+				t.Fatal("breaker let a second request through during the trial")
+			}
+
+			if tc.succeeded {
+				b.Success()
+			} else {
+				b.Failure("timeout")
+			}
+			assertState(t, b, tc.want)
+			if b.Allow() != tc.succeeded {
+				t.Fatalf("Allow() = %v right after the trial", !tc.succeeded)
+			}
+		})
+	}
+}
+
+func TestBreaker_CooldownRestartsAfterFailedTrial(t *testing.T) {
+	b, clock := newTestBreaker(1, 10*time.Second)
+	b.Failure("timeout")
+
+	clock.now = clock.now.Add(10 * time.Second)
+	b.Allow()
+	b.Failure("timeout")
+
+	clock.now = clock.now.Add(9 * time.Second)
+	if b.Allow() {
+		t.Fatal("cooldown did not restart after the failed trial")
+	}
+	clock.now = clock.now.Add(time.Second)
+	if !b.Allow() {
+		t.Fatal("breaker refused the next trial")
+	}
+}
+
+func TestBreaker_StuckTrialIsReplaced(t *testing.T) {
+	b, clock := newTestBreaker(1, 10*time.Second)
+	b.Failure("timeout")
+
+	clock.now = clock.now.Add(10 * time.Second)
+	b.Allow()
+
+	clock.now = clock.now.Add(10 * time.Second)
+	if !b.Allow() {
+		t.Fatal("breaker never replaced a trial that did not report back")
+	}
+	assertState(t, b, CircuitHalfOpen)
+}
@@ -0,0 +1,238 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/config"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/registry"
+)
+
+const healthPath = "/health"
+
+type ServiceStatus struct {
+	Name                string       `json:"name"`
+	Backends            []string     `json:"backends"`
+	Probed              bool         `json:"probed"`
+	Healthy             bool         `json:"healthy"`
+	Circuit             CircuitState `json:"circuit"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	LastError           string       `json:"last_error,omitempty"`
+	LastCheck           *time.Time   `json:"last_check,omitempty"`
+}
+
+type serviceHealth struct {
+	breaker   *Breaker
+	lastCheck time.Time
+}
+
+type Checker struct {
+	reg       *registry.Registry
+	interval  time.Duration
+	timeout   time.Duration
+	threshold int
+	cooldown  time.Duration
+	client    *http.Client
+
+	services map[string]*serviceHealth
+	mu       sync.Mutex
+}
+
+func NewChecker(cfg *config.GatewayConfig, reg *registry.Registry) *Checker {
+	timeout := time.Duration(cfg.HealthCheckTimeoutMs) * time.Millisecond
+	return &Checker{
+		reg:       reg,
+		interval:  time.Duration(cfg.HealthCheckIntervalMs) * time.Millisecond,
+		timeout:   timeout,
+		threshold: cfg.CircuitFailureThreshold,
+		cooldown:  time.Duration(cfg.CircuitOpenMs) * time.Millisecond,
+		client:    &http.Client{Timeout: timeout},
+		services:  make(map[string]*serviceHealth),
+	}
+}
+
+func (c *Checker) entry(service string) *serviceHealth {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sh, ok := c.services[service]
+	if !ok {
+		sh = &serviceHealth{breaker: NewBreaker(c.threshold, c.cooldown)}
+		c.services[service] = sh
+	}
+	return sh
+}
+
+func (c *Checker) Breaker(service string) *Breaker {
+	return c.entry(service).breaker
+}
+
+func (c *Checker) Start() {
+	if c.interval <= 0 {
+		return
+	}
+	go func() {
+		c.probeAll()
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			c.probeAll()
+		}
+	}()
+}
+
+func exposesHealth(reg *registry.Registry, service string) bool {
+	for _, rt := range reg.ListAllRoutes() {
+		if rt.ServiceName == service && rt.Path == healthPath && slices.Contains(rt.Methods, http.MethodGet) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Checker) probeAll() {
+	services := c.reg.ListServices()
+
+	known := make(map[string]struct{}, len(services))
+	var wg sync.WaitGroup
+	for _, svc := range services {
+		known[svc.Name] = struct{}{}
+		if !exposesHealth(c.reg, svc.Name) {
+			continue
+		}
+		wg.Add(1)
+		go func(svc config.ServiceConfig) {
+			defer wg.Done()
+			c.probe(svc)
+		}(svc)
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	for name := range c.services {
+		if _, ok := known[name]; !ok {
+			delete(c.services, name)
+		}
+	}
+	c.mu.Unlock()
+}
+
+func (c *Checker) probe(svc config.ServiceConfig) {
+	sh := c.entry(svc.Name)
+
+	// an open circuit is only probed once its cooldown is over, as the
+	// half-open trial, so that one good answer does not close it early
+	if state, _, _ := sh.breaker.snapshot(); state == CircuitOpen && !sh.breaker.Allow() {
+		return
+	}
+
+	// the service is healthy as long as one of its instances answers
+	var err error
+	for _, backend := range svc.Backends() {
+		ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		err = c.doProbe(ctx, strings.TrimSuffix(backend, "/")+healthPath)
+		cancel()
+		if err == nil {
+			break
+		}
+	}
+
+	c.mu.Lock()
+	sh.lastCheck = time.Now()
+	c.mu.Unlock()
+
+	if err != nil {
+		prev, _, _ := sh.breaker.snapshot()
+		sh.breaker.Failure(err.Error())
+		if state, _, _ := sh.breaker.snapshot(); state == CircuitOpen && prev != CircuitOpen {
+			log.Printf("[WARN] circuit opened for %s: %v", svc.Name, err)
+		}
+		return
+	}
+
+	if prev, _, _ := sh.breaker.snapshot(); prev != CircuitClosed {
+		log.Printf("[INFO] circuit closed for %s", svc.Name)
+	}
+	sh.breaker.Success()
+}
+
+func (c *Checker) doProbe(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("health probe returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *Checker) Statuses() []ServiceStatus {
+	services := c.reg.ListServices()
+	statuses := make([]ServiceStatus, 0, len(services))
+
+	for _, svc := range services {
+		sh := c.entry(svc.Name)
+		state, failures, lastErr := sh.breaker.snapshot()
+
+		c.mu.Lock()
+		lastCheck := sh.lastCheck
+		c.mu.Unlock()
+
+		status := ServiceStatus{
+			Name:                svc.Name,
+			Backends:            svc.Backends(),
+			Probed:              exposesHealth(c.reg, svc.Name),
+			Healthy:             state == CircuitClosed,
+			Circuit:             state,
+			ConsecutiveFailures: failures,
+			LastError:           lastErr,
+		}
+		if !lastCheck.IsZero() {
+			status.LastCheck = &lastCheck
+		}
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+func (c *Checker) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statuses := c.Statuses()
+
+		overall := "ok"
+		for _, s := range statuses {
+			if !s.Healthy {
+				overall = "degraded"
+				break
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"status":   overall,
+			"services": statuses,
+		})
+	})
+}
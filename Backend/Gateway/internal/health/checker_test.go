@@ -0,0 +1,46 @@
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/config"
+)
+
+func TestChecker_ProbeGoesThroughHalfOpen(t *testing.T) {
+	probes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probes++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := &Checker{
+		timeout:   time.Second,
+		threshold: 1,
+		cooldown:  10 * time.Second,
+		client:    server.Client(),
+		services:  make(map[string]*serviceHealth),
+	}
+	svc := config.ServiceConfig{Name: "svc", BaseURL: server.URL}
+	b := c.Breaker(svc.Name)
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	b.now = clock.Now
+	b.Failure("timeout")
+
+	clock.now = clock.now.Add(9 * time.Second)
+	c.probe(svc)
+	assertState(t, b, CircuitOpen)
+	if probes != 0 {
+		t.Fatalf("probed %d times during the cooldown", probes)
+	}
+
+	clock.now = clock.now.Add(time.Second)
+	c.probe(svc)
+	assertState(t, b, CircuitClosed)
+	if probes != 1 {
+		t.Fatalf("probes = %d, want 1", probes)
+	}
+}
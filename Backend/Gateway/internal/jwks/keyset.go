@@ -0,0 +1,259 @@
+package jwks
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var ErrKeyNotFound = errors.New("signing key not found")
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type Key struct {
+	ID        string
+	Algorithm string
+	PublicKey any
+
+	retiredAt time.Time
+}
+
+// KeySet caches the verification keys published at a JWKS URL. Keys are
+// parsed once per fetch. A key that disappears from the document stays usable
+// for `grace`, so tokens signed before a rotation remain valid while both keys
+// overlap.
+type KeySet struct {
+	url         string
+	interval    time.Duration
+	minInterval time.Duration
+	grace       time.Duration
+	client      *http.Client
+
+	keys        map[string]*Key
+	lastAttempt time.Time
+	mu          sync.RWMutex
+	fetchMu     sync.Mutex
+}
+
+func NewKeySet(url string, interval, minInterval, grace time.Duration) *KeySet {
+	return &KeySet{
+		url:         url,
+		interval:    interval,
+		minInterval: minInterval,
+		grace:       grace,
+		client:      &http.Client{Timeout: 5 * time.Second},
+		keys:        make(map[string]*Key),
+	}
+}
+
+func (ks *KeySet) Start() {
+	if err := ks.Refresh(); err != nil {
+		log.Printf("[WARN] initial JWKS fetch from %s failed: %v", ks.url, err)
+	}
+	if ks.interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(ks.interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := ks.Refresh(); err != nil {
+				log.Printf("[WARN] JWKS refresh from %s failed, keeping cached keys: %v", ks.url, err)
+			}
+		}
+	}()
+}
+
+// Key returns the key with the given kid. An unknown kid triggers a refetch,
+// throttled by `minInterval`, so a freshly rotated key is picked up without
+// waiting for the next scheduled refresh.
+func (ks *KeySet) Key(kid string) (*Key, error) {
+	if key := ks.lookup(kid); key != nil {
+		return key, nil
+	}
+
+	ks.fetchMu.Lock()
+	throttled := time.Since(ks.lastAttempt) < ks.minInterval
+	ks.fetchMu.Unlock()
+	if throttled {
+		return nil, ErrKeyNotFound
+	}
+
+	if err := ks.Refresh(); err != nil {
+		log.Printf("[WARN] JWKS refresh from %s failed: %v", ks.url, err)
+	}
+	if key := ks.lookup(kid); key != nil {
+		return key, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+func (ks *KeySet) lookup(kid string) *Key {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if kid == "" {
+		// tokens without kid are only accepted while the set holds a single key
+		if len(ks.keys) != 1 {
+			return nil
+		}
+		for _, key := range ks.keys {
+			kid = key.ID
+		}
+	}
+
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil
+	}
+	if !key.retiredAt.IsZero() && time.Since(key.retiredAt) > ks.grace {
+		return nil
+	}
+	return key
+}
+
+func (ks *KeySet) Refresh() error {
+	ks.fetchMu.Lock()
+	defer ks.fetchMu.Unlock()
+
+	ks.lastAttempt = time.Now()
+
+	fetched, err := ks.fetch()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	for kid, old := range ks.keys {
+		if _, ok := fetched[kid]; ok {
+			continue
+		}
+		if old.retiredAt.IsZero() {
+			old.retiredAt = now
+		}
+		if now.Sub(old.retiredAt) <= ks.grace {
+			fetched[kid] = old
+		}
+	}
+
+	ks.keys = fetched
+	return nil
+}
+
+func (ks *KeySet) fetch() (map[string]*Key, error) {
+	resp, err := ks.client.Get(ks.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("JWKS endpoint returned status %d", resp.StatusCode)
+	}
+
+	var doc struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JWKS document: %w", err)
+	}
+
+	keys := make(map[string]*Key, len(doc.Keys))
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		pub, err := jwk.publicKey()
+		if err != nil {
+			log.Printf("[WARN] skipping JWKS key %q: %v", jwk.Kid, err)
+			continue
+		}
+		keys[jwk.Kid] = &Key{
+			ID:        jwk.Kid,
+			Algorithm: jwk.Alg,
+			PublicKey: pub,
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS document has no usable signing key")
+	}
+	return keys, nil
+}
+
+func (jwk jsonWebKey) publicKey() (any, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil || !e.IsInt64() {
+			return nil, errors.New("invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate: %w", err)
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate: %w", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", jwk.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, errors.New("empty value")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// APIKeyVerifier resolves the raw key of an `Authorization: ApiKey` header to
+// the identity it was issued for. It returns ErrInvalidAPIKey for unknown,
+// expired or revoked keys; any other error means the check could not be done.
+type APIKeyVerifier interface {
+	Verify(ctx context.Context, rawKey string) (*UserContext, error)
+}
+
+type apiKeyCacheEntry struct {
+	user      *UserContext
+	err       error
+	expiresAt time.Time
+}
+
+// HTTPAPIKeyVerifier asks AuthService to verify keys and caches the answers,
+// positive and negative, for `ttl`. A revoked key is thus refused at most
+// `ttl` after its revocation.
+type HTTPAPIKeyVerifier struct {
+	url            string
+	internalSecret string
+	ttl            time.Duration
+	client         *http.Client
+
+	cache map[string]apiKeyCacheEntry
+	mu    sync.Mutex
+}
+
+func NewHTTPAPIKeyVerifier(url, internalSecret string, ttl time.Duration) *HTTPAPIKeyVerifier {
+	v := &HTTPAPIKeyVerifier{
+		url:            url,
+		internalSecret: internalSecret,
+		ttl:            ttl,
+		client:         &http.Client{Timeout: 3 * time.Second},
+		cache:          make(map[string]apiKeyCacheEntry),
+	}
+	go v.sweep()
+	return v
+}
+
+func (v *HTTPAPIKeyVerifier) sweep() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		v.mu.Lock()
+		for k, e := range v.cache {
+			if now.After(e.expiresAt) {
+				delete(v.cache, k)
+			}
+		}
+		v.mu.Unlock()
+	}
+}
+
+func (v *HTTPAPIKeyVerifier) Verify(ctx context.Context, rawKey string) (*UserContext, error) {
+	sum := sha256.Sum256([]byte(rawKey))
+	cacheKey := hex.EncodeToString(sum[:])
+
+	v.mu.Lock()
+	entry, ok := v.cache[cacheKey]
+	v.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.user, entry.err
+	}
+
+	user, keyExpiresAt, err := v.fetch(ctx, rawKey)
+	if err != nil && !errors.Is(err, ErrInvalidAPIKey) {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(v.ttl)
+	if err == nil && keyExpiresAt.Before(expiresAt) {
+		expiresAt = keyExpiresAt
+	}
+
+	v.mu.Lock()
+	v.cache[cacheKey] = apiKeyCacheEntry{user: user, err: err, expiresAt: expiresAt}
+	v.mu.Unlock()
+
+	return user, err
+}
+
+func (v *HTTPAPIKeyVerifier) fetch(ctx context.Context, rawKey string) (*UserContext, time.Time, error) {
+	payload, err := json.Marshal(map[string]string{"key": rawKey})
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Internal-Secret", v.internalSecret)
+	if requestID := GetRequestIDFromContext(ctx); requestID != "" {
+		req.Header.Set(RequestIDHeader, requestID)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, time.Time{}, ErrInvalidAPIKey
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, time.Time{}, fmt.Errorf("api key verification returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data struct {
+			UserID      int       `json:"user_id"`
+			Email       string    `json:"email"`
+			Permissions []string  `json:"permissions"`
+			ExpiresAt   time.Time `json:"expires_at"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid api key verification response: %w", err)
+	}
+	if body.Data.UserID <= 0 {
+		return nil, time.Time{}, ErrInvalidAPIKey
+	}
+
+	return &UserContext{
+		UserID: strconv.Itoa(body.Data.UserID),
+		Email:  body.Data.Email,
+		APIKey: true,
+		Scopes: body.Data.Permissions,
+	}, body.Data.ExpiresAt, nil
+}
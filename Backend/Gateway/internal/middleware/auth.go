@@ -2,9 +2,13 @@ package middleware
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
@@ -12,7 +16,10 @@ import (
 
 	"github.com/raphael-guer1n/AREA/area-gateway/internal/config"
 	"github.com/raphael-guer1n/AREA/area-gateway/internal/core"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/jwks"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/metrics"
 	"github.com/raphael-guer1n/AREA/area-gateway/internal/registry"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/revocation"
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -24,6 +31,11 @@ type UserContext struct {
 	UserID      string
 	Email       string
 	Permissions []string
+
+	// APIKey is set when the request is authenticated with an API key, whose
+	// scopes are in Scopes. Scopes never grant permissions.
+	APIKey bool
+	Scopes []string
 }
 
 func GetUserFromContext(ctx context.Context) *UserContext {
@@ -43,16 +55,37 @@ type AuthMiddleware struct {
 	PublicKey []byte
 	Secret    []byte
 
-	reg *registry.Registry
+	rsaKey  *rsa.PublicKey
+	keys    *jwks.KeySet
+	apiKeys APIKeyVerifier
+	revoked *revocation.DenyList
+	reg     *registry.Registry
 }
 
-func NewAuthMiddleware(cfg *config.GatewayConfig, reg *registry.Registry) *AuthMiddleware {
-	return &AuthMiddleware{
+// NewAuthMiddleware verifies tokens against the JWKS key set when one is
+// given, otherwise against the static JWT_PUBLIC_KEY / JWT_SECRET. API keys
+// are only accepted when an APIKeyVerifier is given, and revoked tokens only
+// refused when a DenyList is given.
+func NewAuthMiddleware(cfg *config.GatewayConfig, reg *registry.Registry, keys *jwks.KeySet, apiKeys APIKeyVerifier, revoked *revocation.DenyList) *AuthMiddleware {
+	a := &AuthMiddleware{
 		Algorithm: cfg.JwtAlgorithm,
 		PublicKey: []byte(cfg.JwtPublicKey),
 		Secret:    []byte(cfg.JwtSecret),
+		keys:      keys,
+		apiKeys:   apiKeys,
+		revoked:   revoked,
 		reg:       reg,
 	}
+
+	if keys == nil && a.Algorithm == "RS256" && len(a.PublicKey) > 0 {
+		key, err := jwt.ParseRSAPublicKeyFromPEM(a.PublicKey)
+		if err != nil {
+			log.Printf("[ERROR] invalid JWT_PUBLIC_KEY: %v", err)
+		} else {
+			a.rsaKey = key
+		}
+	}
+	return a
 }
 
 func parseExpClaim(value interface{}) (int64, error) {
@@ -72,10 +105,86 @@ func parseExpClaim(value interface{}) (int64, error) {
 	}
 }
 
+// parseIssuedAtClaim keeps the milliseconds of a fractional `iat`, which the
+// token deny-list compares to the revocation cutoffs.
+func parseIssuedAtClaim(value interface{}) (time.Time, error) {
+	var seconds float64
+	switch v := value.(type) {
+	case float64:
+		seconds = v
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return time.Time{}, err
+		}
+		seconds = f
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		seconds = f
+	default:
+		return time.Time{}, fmt.Errorf("unsupported iat type: %T", value)
+	}
+	return time.UnixMilli(int64(math.Round(seconds * 1000))), nil
+}
+
+func (a *AuthMiddleware) jwksKey(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	key, err := a.keys.Key(kid)
+	if err != nil {
+		return nil, fmt.Errorf("kid %q: %w", kid, err)
+	}
+
+	if key.Algorithm != "" && key.Algorithm != t.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method for kid %q: %s", kid, t.Method.Alg())
+	}
+
+	switch key.PublicKey.(type) {
+	case *rsa.PublicKey:
+		switch t.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+			return key.PublicKey, nil
+		}
+	case *ecdsa.PublicKey:
+		if _, ok := t.Method.(*jwt.SigningMethodECDSA); ok {
+			return key.PublicKey, nil
+		}
+	}
+	return nil, fmt.Errorf("signing method %s does not match key %q", t.Method.Alg(), kid)
+}
+
+func (a *AuthMiddleware) reject(w http.ResponseWriter, status int, code string, msg string) {
+	metrics.AuthFailuresTotal.Inc(code)
+	core.WriteError(w, status, code, msg)
+}
+
+func (a *AuthMiddleware) serveAPIKey(w http.ResponseWriter, r *http.Request, rawKey string, next http.Handler) {
+	if rawKey == "" {
+		a.reject(w, http.StatusUnauthorized, core.ErrInvalidToken, "Empty API key")
+		return
+	}
+
+	userCtx, err := a.apiKeys.Verify(r.Context(), rawKey)
+	if errors.Is(err, ErrInvalidAPIKey) {
+		a.reject(w, http.StatusUnauthorized, core.ErrInvalidToken, "Invalid, expired or revoked API key")
+		return
+	}
+	if err != nil {
+		log.Printf("[ERROR] api key verification failed: %v", err)
+		a.reject(w, http.StatusBadGateway, core.ErrBadGateway, "API key verification unavailable")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), userContextKey, userCtx)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
 func (a *AuthMiddleware) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
-		route, err := a.reg.FindRoute(r.URL.Path, r.Method)
+		route, err := matchedRoute(a.reg, r)
 		if err == nil && !route.AuthRequired {
 			next.ServeHTTP(w, r)
 			return
@@ -83,28 +192,32 @@ func (a *AuthMiddleware) Handler(next http.Handler) http.Handler {
 
 		auth := r.Header.Get("Authorization")
 		if auth == "" {
-			core.WriteError(w, http.StatusUnauthorized, core.ErrMissingToken, "Missing Authorization header")
+			a.reject(w, http.StatusUnauthorized, core.ErrMissingToken, "Missing Authorization header")
 			return
 		}
 
 		parts := strings.Split(auth, " ")
+		if len(parts) == 2 && parts[0] == "ApiKey" && a.apiKeys != nil {
+			a.serveAPIKey(w, r, parts[1], next)
+			return
+		}
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			core.WriteError(w, http.StatusUnauthorized, core.ErrInvalidAuthHeader, "Authorization must be Bearer <token>")
+			a.reject(w, http.StatusUnauthorized, core.ErrInvalidAuthHeader, "Authorization must be Bearer <token>")
 			return
 		}
 
 		rawToken := parts[1]
 		if rawToken == "" {
-			core.WriteError(w, http.StatusUnauthorized, core.ErrInvalidToken, "Empty token")
+			a.reject(w, http.StatusUnauthorized, core.ErrInvalidToken, "Empty token")
 			return
 		}
 
-		if a.Algorithm == "RS256" && len(a.PublicKey) == 0 {
-			core.WriteError(w, 500, core.ErrInternalError, "RS256 requires JWT_PUBLIC_KEY")
+		if a.keys == nil && a.Algorithm == "RS256" && a.rsaKey == nil {
+			a.reject(w, 500, core.ErrInternalError, "RS256 requires a valid JWT_PUBLIC_KEY")
 			return
 		}
-		if a.Algorithm == "HS256" && len(a.Secret) == 0 {
-			core.WriteError(w, 500, core.ErrInternalError, "HS256 requires JWT_SECRET")
+		if a.keys == nil && a.Algorithm == "HS256" && len(a.Secret) == 0 {
+			a.reject(w, 500, core.ErrInternalError, "HS256 requires JWT_SECRET")
 			return
 		}
 
@@ -114,16 +227,16 @@ func (a *AuthMiddleware) Handler(next http.Handler) http.Handler {
 				return nil, errors.New("alg=none is forbidden")
 			}
 
+			if a.keys != nil {
+				return a.jwksKey(t)
+			}
+
 			if t.Method.Alg() != a.Algorithm {
 				return nil, fmt.Errorf("unexpected signing method: %s", t.Method.Alg())
 			}
 
 			if a.Algorithm == "RS256" {
-				key, err := jwt.ParseRSAPublicKeyFromPEM(a.PublicKey)
-				if err != nil {
-					return nil, errors.New("invalid RSA public key")
-				}
-				return key, nil
+				return a.rsaKey, nil
 			}
 
 			if a.Algorithm == "HS256" {
@@ -134,39 +247,51 @@ func (a *AuthMiddleware) Handler(next http.Handler) http.Handler {
 		})
 
 		if err != nil || !token.Valid {
-			core.WriteError(w, http.StatusUnauthorized, core.ErrInvalidToken, "Invalid or expired token")
+			a.reject(w, http.StatusUnauthorized, core.ErrInvalidToken, "Invalid or expired token")
 			return
 		}
 
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok {
-			core.WriteError(w, http.StatusUnauthorized, core.ErrInvalidToken, "Token claims malformed")
+			a.reject(w, http.StatusUnauthorized, core.ErrInvalidToken, "Token claims malformed")
 			return
 		}
 
 		expValue, ok := claims["exp"]
 		if !ok {
-			core.WriteError(w, http.StatusUnauthorized, core.ErrInvalidToken, "Missing exp claim")
+			a.reject(w, http.StatusUnauthorized, core.ErrInvalidToken, "Missing exp claim")
 			return
 		}
 
 		exp, err := parseExpClaim(expValue)
 		if err != nil {
-			core.WriteError(w, http.StatusUnauthorized, core.ErrInvalidToken, "Invalid exp format")
+			a.reject(w, http.StatusUnauthorized, core.ErrInvalidToken, "Invalid exp format")
 			return
 		}
 
 		if time.Unix(exp, 0).Before(time.Now()) {
-			core.WriteError(w, http.StatusUnauthorized, core.ErrInvalidToken, "Token expired")
+			a.reject(w, http.StatusUnauthorized, core.ErrInvalidToken, "Token expired")
 			return
 		}
 
 		uid, _ := claims["sub"].(string)
 		if uid == "" {
-			core.WriteError(w, http.StatusUnauthorized, core.ErrInvalidToken, "Missing sub claim")
+			a.reject(w, http.StatusUnauthorized, core.ErrInvalidToken, "Missing sub claim")
 			return
 		}
 
+		if a.revoked != nil {
+			jti, _ := claims["jti"].(string)
+			var iat time.Time
+			if v, ok := claims["iat"]; ok {
+				iat, _ = parseIssuedAtClaim(v)
+			}
+			if a.revoked.IsRevoked(jti, uid, iat) {
+				a.reject(w, http.StatusUnauthorized, core.ErrInvalidToken, "Token has been revoked")
+				return
+			}
+		}
+
 		email, _ := claims["email"].(string)
 
 		var perms []string
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/config"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/core"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/registry"
+)
+
+// BodyMiddleware enforces the request body size and content type of a route,
+// falling back to the gateway-wide limits. It runs before authentication so
+// that oversized uploads are turned away without any other work.
+type BodyMiddleware struct {
+	reg          *registry.Registry
+	maxBodyBytes int
+	contentTypes []string
+}
+
+func NewBodyMiddleware(cfg *config.GatewayConfig, reg *registry.Registry) *BodyMiddleware {
+	return &BodyMiddleware{
+		reg:          reg,
+		maxBodyBytes: cfg.MaxBodyBytes,
+		contentTypes: cfg.AllowedContentTypes,
+	}
+}
+
+func (bm *BodyMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		rt, err := matchedRoute(bm.reg, r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// ContentLength is -1 when the body is chunked
+		if r.ContentLength == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		contentTypes := rt.ContentTypes
+		if len(contentTypes) == 0 {
+			contentTypes = bm.contentTypes
+		}
+		if len(contentTypes) > 0 && !contentTypeAllowed(r.Header.Get("Content-Type"), contentTypes) {
+			core.WriteError(
+				w,
+				http.StatusUnsupportedMediaType,
+				core.ErrUnsupportedMedia,
+				"Content-Type must be one of: "+strings.Join(contentTypes, ", "),
+			)
+			return
+		}
+
+		limit := rt.MaxBodyBytes
+		if limit == 0 {
+			limit = bm.maxBodyBytes
+		}
+		if limit > 0 {
+			if r.ContentLength > int64(limit) {
+				core.WriteError(w, http.StatusRequestEntityTooLarge, core.ErrPayloadTooLarge, "Request body too large")
+				return
+			}
+			// chunked bodies are cut while being proxied, see the proxy error handler
+			r.Body = http.MaxBytesReader(w, r.Body, int64(limit))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func contentTypeAllowed(header string, allowed []string) bool {
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return false
+	}
+	for _, ct := range allowed {
+		ct = strings.ToLower(ct)
+		if ct == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(ct, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
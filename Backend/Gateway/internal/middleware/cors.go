@@ -57,9 +57,11 @@ func (c *CORSMiddleware) Handler(next http.Handler) http.Handler {
 
 		w.Header().Set(
 			"Access-Control-Allow-Headers",
-			"Content-Type, Authorization",
+			"Content-Type, Authorization, X-Request-ID",
 		)
 
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
+
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
 			return
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/config"
+)
+
+const (
+	UserIDHeader          = "X-User-ID"
+	UserEmailHeader       = "X-User-Email"
+	UserPermissionsHeader = "X-User-Permissions"
+	UserTimestampHeader   = "X-User-Timestamp"
+	UserSignatureHeader   = "X-User-Signature"
+)
+
+var identityHeaders = []string{
+	UserIDHeader,
+	UserEmailHeader,
+	UserPermissionsHeader,
+	UserTimestampHeader,
+	UserSignatureHeader,
+}
+
+// IdentityMiddleware replaces any client-supplied identity headers with the
+// ones of the verified token. The headers are signed with the internal secret
+// so services can trust them without calling /auth/me.
+type IdentityMiddleware struct {
+	internalSecret []byte
+}
+
+func NewIdentityMiddleware(cfg *config.GatewayConfig) *IdentityMiddleware {
+	return &IdentityMiddleware{
+		internalSecret: []byte(cfg.InternalSecret),
+	}
+}
+
+func SignIdentity(secret []byte, userID, email, permissions, timestamp string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(userID + "\n" + email + "\n" + permissions + "\n" + timestamp))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (im *IdentityMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		for _, h := range identityHeaders {
+			r.Header.Del(h)
+		}
+
+		user := GetUserFromContext(r.Context())
+		if user != nil && len(im.internalSecret) > 0 {
+			permissions := strings.Join(user.Permissions, ",")
+			timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+			r.Header.Set(UserIDHeader, user.UserID)
+			r.Header.Set(UserEmailHeader, user.Email)
+			r.Header.Set(UserPermissionsHeader, permissions)
+			r.Header.Set(UserTimestampHeader, timestamp)
+			r.Header.Set(UserSignatureHeader, SignIdentity(im.internalSecret, user.UserID, user.Email, permissions, timestamp))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -23,7 +23,7 @@ func NewInternalMiddleware(cfg *config.GatewayConfig, reg *registry.Registry) *I
 func (im *InternalMiddleware) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
-		rt, err := im.reg.FindRoute(r.URL.Path, r.Method)
+		rt, err := matchedRoute(im.reg, r)
 		if err != nil {
 			core.WriteError(w, http.StatusInternalServerError, core.ErrNotFound, "Route not found in registry")
 			return
@@ -34,22 +34,43 @@ func (im *InternalMiddleware) Handler(next http.Handler) http.Handler {
 			return
 		}
 
-		if im.internalSecret == "" {
-			core.WriteError(w, http.StatusInternalServerError, core.ErrInternalError, "Internal secret not configured")
+		if !im.checkSecret(w, r) {
 			return
 		}
 
-		headerSecret := r.Header.Get("X-Internal-Secret")
-		if headerSecret == "" || headerSecret != im.internalSecret {
-			core.WriteError(
-				w,
-				http.StatusForbidden,
-				core.ErrForbidden,
-				"Access to internal endpoint denied",
-			)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Guard restricts a gateway-owned endpoint, which has no registry route, to
+// callers presenting the internal secret.
+func (im *InternalMiddleware) Guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !im.checkSecret(w, r) {
 			return
 		}
 
 		next.ServeHTTP(w, r)
 	})
 }
+
+// checkSecret tells whether the request presents the internal secret, and
+// writes the error response when it does not.
+func (im *InternalMiddleware) checkSecret(w http.ResponseWriter, r *http.Request) bool {
+	if im.internalSecret == "" {
+		core.WriteError(w, http.StatusInternalServerError, core.ErrInternalError, "Internal secret not configured")
+		return false
+	}
+
+	headerSecret := r.Header.Get("X-Internal-Secret")
+	if headerSecret == "" || headerSecret != im.internalSecret {
+		core.WriteError(
+			w,
+			http.StatusForbidden,
+			core.ErrForbidden,
+			"Access to internal endpoint denied",
+		)
+		return false
+	}
+	return true
+}
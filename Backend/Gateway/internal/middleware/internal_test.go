@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/config"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/registry"
+)
+
+func TestInternalMiddleware_Secret(t *testing.T) {
+	testCases := []struct {
+		name       string
+		configured string
+		presented  string
+		status     int
+	}{
+		{"matching secret", "secret", "secret", http.StatusOK},
+		{"wrong secret", "secret", "guess", http.StatusForbidden},
+		{"no secret presented", "secret", "", http.StatusForbidden},
+		{"secret not configured", "", "", http.StatusInternalServerError},
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			im := NewInternalMiddleware(&config.GatewayConfig{InternalSecret: tc.configured}, registry.NewRegistry())
+			ctx := registry.WithRoute(context.Background(), &registry.RegisteredRoute{InternalOnly: true})
+
+			for name, handler := range map[string]http.Handler{"Handler": im.Handler(ok), "Guard": im.Guard(ok)} {
+				req := httptest.NewRequest(http.MethodGet, "/internal", nil).WithContext(ctx)
+				if tc.presented != "" {
+					req.Header.Set("X-Internal-Secret", tc.presented)
+				}
+				rec := httptest.NewRecorder()
+
+				handler.ServeHTTP(rec, req)
+
+				if rec.Code != tc.status {
+					t.Fatalf("%s: status = %d, want %d", name, rec.Code, tc.status)
+				}
+			}
+		})
+	}
+}
@@ -1,7 +1,12 @@
 package middleware
 
 import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"log"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -39,6 +44,40 @@ func requestSource(r *http.Request) string {
 	return r.RemoteAddr
 }
 
+const RequestIDHeader = "X-Request-ID"
+
+const requestIDContextKey = contextKey("request_id")
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return strings.ReplaceAll(time.Now().UTC().Format("20060102150405.000000000"), ".", "")
+	}
+	return hex.EncodeToString(buf)
+}
+
+// validRequestID accepts ids forwarded by clients or upstream proxies as long
+// as they cannot be used to inject anything into headers or log lines.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func GetRequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+	return id
+}
+
 type wrappedResponseWriter struct {
 	http.ResponseWriter
 	status int
@@ -49,6 +88,20 @@ func (w *wrappedResponseWriter) WriteHeader(code int) {
 	w.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer, so
+// streamed responses can be flushed through the middleware chain.
+func (w *wrappedResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *wrappedResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
 type LoggingMiddleware struct {
 	reg *registry.Registry
 }
@@ -64,6 +117,14 @@ func (lm *LoggingMiddleware) Handler(next http.Handler) http.Handler {
 
 		start := time.Now()
 
+		requestID := strings.TrimSpace(r.Header.Get(RequestIDHeader))
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		r.Header.Set(RequestIDHeader, requestID)
+		w.Header().Set(RequestIDHeader, requestID)
+		r = r.WithContext(context.WithValue(r.Context(), requestIDContextKey, requestID))
+
 		ww := &wrappedResponseWriter{
 			ResponseWriter: w,
 			status:         http.StatusOK,
@@ -74,7 +135,7 @@ func (lm *LoggingMiddleware) Handler(next http.Handler) http.Handler {
 		duration := time.Since(start)
 
 		internalFlag := ""
-		if rt, err := lm.reg.FindRoute(r.URL.Path, r.Method); err == nil && rt.InternalOnly {
+		if rt, err := matchedRoute(lm.reg, r); err == nil && rt.InternalOnly {
 			internalFlag = "[INTERNAL] "
 		}
 
@@ -90,13 +151,14 @@ func (lm *LoggingMiddleware) Handler(next http.Handler) http.Handler {
 		}
 
 		log.Printf(
-			"%s %s%s %d %s src=%s %dms",
+			"%s %s%s %d %s src=%s rid=%s %dms",
 			level,
 			internalFlag,
 			r.Method+" "+r.URL.Path,
 			ww.status,
 			userInfo,
 			requestSource(r),
+			requestID,
 			duration.Milliseconds(),
 		)
 	})
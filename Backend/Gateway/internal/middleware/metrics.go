@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/metrics"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/registry"
+)
+
+type MetricsMiddleware struct{}
+
+func NewMetricsMiddleware() *MetricsMiddleware {
+	return &MetricsMiddleware{}
+}
+
+func (mm *MetricsMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		start := time.Now()
+
+		ww := &wrappedResponseWriter{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+		}
+
+		next.ServeHTTP(ww, r)
+
+		// label by route pattern, never by raw path, to keep cardinality bounded
+		route := "unmatched"
+		if rt := registry.RouteFromContext(r.Context()); rt != nil {
+			route = rt.NamespacedPath
+		}
+
+		metrics.ObserveRequest(route, r.Method, ww.status, time.Since(start))
+	})
+}
@@ -20,7 +20,7 @@ func NewPermissionsMiddleware(reg *registry.Registry) *PermissionsMiddleware {
 func (pm *PermissionsMiddleware) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
-		rt, err := pm.reg.FindRoute(r.URL.Path, r.Method)
+		rt, err := matchedRoute(pm.reg, r)
 		if err != nil {
 			core.WriteError(w, http.StatusInternalServerError, core.ErrNotFound, "Route not found in registry")
 			return
@@ -37,6 +37,11 @@ func (pm *PermissionsMiddleware) Handler(next http.Handler) http.Handler {
 			return
 		}
 
+		if user.APIKey && !hasAnyScope(user.Scopes, rt.Scopes) {
+			core.WriteError(w, http.StatusForbidden, core.ErrForbidden, "API key not allowed on this route")
+			return
+		}
+
 		if len(rt.Permissions) == 0 {
 			next.ServeHTTP(w, r)
 			return
@@ -62,3 +67,14 @@ func (pm *PermissionsMiddleware) Handler(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+func hasAnyScope(held, accepted []string) bool {
+	for _, scope := range accepted {
+		for _, h := range held {
+			if h == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/registry"
+)
+
+func TestPermissionsMiddleware_APIKeyScopes(t *testing.T) {
+	testCases := []struct {
+		name   string
+		route  registry.RegisteredRoute
+		user   *UserContext
+		status int
+	}{
+		{
+			name:   "jwt on a route without scopes",
+			route:  registry.RegisteredRoute{AuthRequired: true},
+			user:   &UserContext{UserID: "1"},
+			status: http.StatusOK,
+		},
+		{
+			name:   "api key on a route without scopes",
+			route:  registry.RegisteredRoute{AuthRequired: true},
+			user:   &UserContext{UserID: "1", APIKey: true, Scopes: []string{"areas:write"}},
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "api key holding an accepted scope",
+			route:  registry.RegisteredRoute{AuthRequired: true, Scopes: []string{"areas:read"}},
+			user:   &UserContext{UserID: "1", APIKey: true, Scopes: []string{"areas:read"}},
+			status: http.StatusOK,
+		},
+		{
+			name:   "api key holding another scope",
+			route:  registry.RegisteredRoute{AuthRequired: true, Scopes: []string{"areas:write"}},
+			user:   &UserContext{UserID: "1", APIKey: true, Scopes: []string{"areas:read"}},
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "api key scopes do not grant permissions",
+			route:  registry.RegisteredRoute{AuthRequired: true, Permissions: []string{"admin"}, Scopes: []string{"areas:read"}},
+			user:   &UserContext{UserID: "1", APIKey: true, Scopes: []string{"areas:read", "admin"}},
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "jwt missing a permission",
+			route:  registry.RegisteredRoute{AuthRequired: true, Permissions: []string{"admin"}},
+			user:   &UserContext{UserID: "1"},
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewPermissionsMiddleware(registry.NewRegistry()).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			route := tc.route
+			ctx := registry.WithRoute(context.Background(), &route)
+			ctx = context.WithValue(ctx, userContextKey, tc.user)
+			req := httptest.NewRequest(http.MethodGet, "/getAreas", nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tc.status)
+			}
+		})
+	}
+}
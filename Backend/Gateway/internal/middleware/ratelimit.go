@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/core"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/registry"
+)
+
+// RateLimitStore counts hits per key over fixed windows. Implementations backed
+// by a shared datastore let several gateway replicas enforce the same limits.
+type RateLimitStore interface {
+	Increment(key string, window time.Duration) (count int, resetAt time.Time, err error)
+}
+
+type memoryWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+type MemoryRateLimitStore struct {
+	windows   map[string]*memoryWindow
+	lastSweep time.Time
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		windows:   make(map[string]*memoryWindow),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (s *MemoryRateLimitStore) Increment(key string, window time.Duration) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) > time.Minute {
+		for k, w := range s.windows {
+			if !now.Before(w.resetAt) {
+				delete(s.windows, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	w, ok := s.windows[key]
+	if !ok || !now.Before(w.resetAt) {
+		w = &memoryWindow{resetAt: now.Add(window)}
+		s.windows[key] = w
+	}
+	w.count++
+
+	return w.count, w.resetAt, nil
+}
+
+type RateLimitMiddleware struct {
+	reg   *registry.Registry
+	store RateLimitStore
+	now   func() time.Time
+}
+
+func NewRateLimitMiddleware(reg *registry.Registry, store RateLimitStore) *RateLimitMiddleware {
+	if store == nil {
+		store = NewMemoryRateLimitStore()
+	}
+	return &RateLimitMiddleware{
+		reg:   reg,
+		store: store,
+		now:   time.Now,
+	}
+}
+
+func rateLimitClient(r *http.Request) string {
+	if user := GetUserFromContext(r.Context()); user != nil && user.UserID != "" {
+		return "user:" + user.UserID
+	}
+	return "ip:" + core.ClientIPFromRemoteAddr(r.RemoteAddr)
+}
+
+func (rl *RateLimitMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		rt, err := matchedRoute(rl.reg, r)
+		if err != nil || rt.RateLimit == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		limit := rt.RateLimit.Requests
+		window := time.Duration(rt.RateLimit.PerSec) * time.Second
+		key := rateLimitClient(r) + "|" + r.Method + " " + rt.NamespacedPath
+
+		count, resetAt, err := rl.store.Increment(key, window)
+		if err != nil {
+			log.Printf("[WARN] rate limit store error for %s: %v", key, err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		remaining := limit - count
+		if remaining < 0 {
+			remaining = 0
+		}
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+
+		if count > limit {
+			retryAfter := int(math.Ceil(resetAt.Sub(rl.now()).Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			core.WriteError(w, http.StatusTooManyRequests, core.ErrRateLimited, "Rate limit exceeded")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
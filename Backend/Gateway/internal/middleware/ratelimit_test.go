@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/config"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/registry"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestRateLimiter(clock *fakeClock) *RateLimitMiddleware {
+	store := NewMemoryRateLimitStore()
+	store.now = clock.Now
+	rl := NewRateLimitMiddleware(registry.NewRegistry(), store)
+	rl.now = clock.Now
+	return rl
+}
+
+func rateLimitedRequest(remoteAddr string, user *UserContext) *http.Request {
+	route := &registry.RegisteredRoute{
+		NamespacedPath: "/area_auth_api/auth/login",
+		RateLimit:      &config.RateLimitConfig{Requests: 2, PerSec: 10},
+	}
+	ctx := registry.WithRoute(context.Background(), route)
+	if user != nil {
+		ctx = context.WithValue(ctx, userContextKey, user)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", nil).WithContext(ctx)
+	req.RemoteAddr = remoteAddr
+	return req
+}
+
+func serveRateLimited(rl *RateLimitMiddleware, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	rl.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimitMiddleware_RejectsOverLimit(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	rl := newTestRateLimiter(clock)
+
+	for i, wantRemaining := range []string{"1", "0"} {
+		rec := serveRateLimited(rl, rateLimitedRequest("10.0.0.1:5000", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i+1, rec.Code)
+		}
+		if got := rec.Header().Get("X-RateLimit-Remaining"); got != wantRemaining {
+			t.Fatalf("request %d: X-RateLimit-Remaining = %s, want %s", i+1, got, wantRemaining)
+		}
+	}
+
+	clock.now = clock.now.Add(3 * time.Second)
+	rec := serveRateLimited(rl, rateLimitedRequest("10.0.0.1:5000", nil))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "7" {
+		t.Fatalf("Retry-After = %s, want 7", got)
+	}
+	if got := rec.Header().Get("X-RateLimit-Limit"); got != "2" {
+		t.Fatalf("X-RateLimit-Limit = %s, want 2", got)
+	}
+
+	clock.now = clock.now.Add(7 * time.Second)
+	if rec := serveRateLimited(rl, rateLimitedRequest("10.0.0.1:5000", nil)); rec.Code != http.StatusOK {
+		t.Fatalf("after the window: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestRateLimitMiddleware_Keys(t *testing.T) {
+	testCases := []struct {
+		name    string
+		first   *http.Request
+		second  *http.Request
+		limited bool
+	}{
+		{
+			name:    "same ip",
+			first:   rateLimitedRequest("10.0.0.1:5000", nil),
+			second:  rateLimitedRequest("10.0.0.1:6000", nil),
+			limited: true,
+		},
+		{
+			name:   "other ip",
+			first:  rateLimitedRequest("10.0.0.1:5000", nil),
+			second: rateLimitedRequest("10.0.0.2:5000", nil),
+		},
+		{
+			name:    "same user from two ips",
+			first:   rateLimitedRequest("10.0.0.1:5000", &UserContext{UserID: "7"}),
+			second:  rateLimitedRequest("10.0.0.2:5000", &UserContext{UserID: "7"}),
+			limited: true,
+		},
+		{
+			name:   "two users behind one ip",
+			first:  rateLimitedRequest("10.0.0.1:5000", &UserContext{UserID: "7"}),
+			second: rateLimitedRequest("10.0.0.1:5000", &UserContext{UserID: "8"}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rl := newTestRateLimiter(&fakeClock{now: time.Unix(1700000000, 0)})
+			serveRateLimited(rl, tc.first)
+			serveRateLimited(rl, tc.first)
+
+			rec := serveRateLimited(rl, tc.second)
+			if tc.limited && rec.Code != http.StatusTooManyRequests {
+				t.Fatalf("status = %d, want 429", rec.Code)
+			}
+			if !tc.limited && rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+		})
+	}
+}
+
+func TestRateLimitMiddleware_RouteWithoutLimit(t *testing.T) {
+	rl := newTestRateLimiter(&fakeClock{now: time.Unix(1700000000, 0)})
+	ctx := registry.WithRoute(context.Background(), &registry.RegisteredRoute{NamespacedPath: "/area_auth_api/health"})
+
+	for i := 0; i < 5; i++ {
+		rec := serveRateLimited(rl, httptest.NewRequest(http.MethodGet, "/health", nil).WithContext(ctx))
+		if rec.Code != http.StatusOK || rec.Header().Get("X-RateLimit-Limit") != "" {
+			t.Fatalf("request %d was rate limited", i+1)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/registry"
+)
+
+// matchedRoute returns the route the router resolved for the request, and
+// only falls back to a registry lookup when the middleware runs outside it.
+func matchedRoute(reg *registry.Registry, r *http.Request) (*registry.RegisteredRoute, error) {
+	if rt := registry.RouteFromContext(r.Context()); rt != nil {
+		return rt, nil
+	}
+	return reg.FindRoute(r.URL.Path, r.Method)
+}
@@ -0,0 +1,303 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+var operationMethods = map[string]bool{
+	"get":     true,
+	"put":     true,
+	"post":    true,
+	"delete":  true,
+	"options": true,
+	"head":    true,
+	"patch":   true,
+	"trace":   true,
+}
+
+// Aggregator serves a single OpenAPI document built from the specs of the
+// services: paths are namespaced (`/{service}{path}`), internal-only routes are
+// left out and operations are annotated with the gateway auth policy.
+type Aggregator struct {
+	doc []byte
+	mu  sync.RWMutex
+}
+
+func NewAggregator() *Aggregator {
+	return &Aggregator{}
+}
+
+// Build merges the specs and swaps the served document. It fails when a
+// configured route is missing from the spec of its service, and the previous
+// document is kept.
+func (a *Aggregator) Build(services []config.ServiceConfig) error {
+	publish, err := a.Prepare(services)
+	if err != nil {
+		return err
+	}
+	publish()
+	return nil
+}
+
+// Prepare merges the specs like Build, but leaves the served document alone
+// until the returned function is called.
+func (a *Aggregator) Prepare(services []config.ServiceConfig) (func(), error) {
+	doc, err := merge(services)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+
+	return func() {
+		a.mu.Lock()
+		a.doc = body
+		a.mu.Unlock()
+	}, nil
+}
+
+func (a *Aggregator) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.mu.RLock()
+		body := a.doc
+		a.mu.RUnlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	})
+}
+
+func merge(services []config.ServiceConfig) (map[string]any, error) {
+	sorted := make([]config.ServiceConfig, len(services))
+	copy(sorted, services)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	paths := make(map[string]any)
+	components := map[string]any{
+		"securitySchemes": map[string]any{
+			"bearerAuth": map[string]any{
+				"type":         "http",
+				"scheme":       "bearer",
+				"bearerFormat": "JWT",
+			},
+			"apiKey": map[string]any{
+				"type":        "apiKey",
+				"in":          "header",
+				"name":        "Authorization",
+				"description": "`ApiKey <key>`, with a key issued by `/area_auth_api/auth/api-keys`.",
+			},
+		},
+	}
+	var tags []any
+
+	for _, svc := range sorted {
+		if svc.OpenAPI == "" {
+			log.Printf("[WARN] service %s declares no openapi spec, its routes are left out of the gateway document", svc.Name)
+			continue
+		}
+
+		spec, err := loadSpec(svc.OpenAPI, svc.Name)
+		if err != nil {
+			return nil, fmt.Errorf("service '%s': %w", svc.Name, err)
+		}
+		if err := mergeService(svc, spec, paths, components); err != nil {
+			return nil, fmt.Errorf("service '%s': %w", svc.Name, err)
+		}
+
+		tag := map[string]any{"name": svc.Name}
+		if info, ok := spec["info"].(map[string]any); ok {
+			if title, ok := info["title"].(string); ok {
+				tag["description"] = title
+			}
+		}
+		tags = append(tags, tag)
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":       "AREA API",
+			"version":     "1.0.0",
+			"description": "Public routes of the AREA backend, as exposed by the gateway.",
+		},
+		"tags":       tags,
+		"paths":      paths,
+		"components": components,
+	}, nil
+}
+
+func loadSpec(path, service string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read openapi spec: %w", err)
+	}
+
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse openapi spec %s: %w", path, err)
+	}
+
+	spec, ok := normalize(raw, service).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("openapi spec %s is not an object", path)
+	}
+	return spec, nil
+}
+
+// specShape matches a config path against a spec path. OpenAPI has no
+// multi-segment parameter, so a `{name...}` wildcard is documented as `{name}`.
+func specShape(path string) string {
+	return config.RouteShape(strings.ReplaceAll(path, "...}", "}"))
+}
+
+func mergeService(svc config.ServiceConfig, spec map[string]any, paths map[string]any, components map[string]any) error {
+	specPaths, _ := spec["paths"].(map[string]any)
+	byShape := make(map[string]string, len(specPaths))
+	for p := range specPaths {
+		byShape[specShape(p)] = p
+	}
+
+	for _, route := range svc.Routes {
+		specPath, ok := byShape[specShape(route.Path)]
+		if !ok {
+			return fmt.Errorf("route %s is missing from its openapi spec", route.Path)
+		}
+		item, _ := specPaths[specPath].(map[string]any)
+
+		for _, m := range route.Methods {
+			op, ok := item[strings.ToLower(m)].(map[string]any)
+			if !ok {
+				return fmt.Errorf("route %s %s is missing from its openapi spec", m, route.Path)
+			}
+			if route.InternalOnly {
+				continue
+			}
+
+			outPath := "/" + svc.Name + "/" + strings.TrimPrefix(specPath, "/")
+			out, ok := paths[outPath].(map[string]any)
+			if !ok {
+				out = make(map[string]any)
+				for k, v := range item {
+					if !operationMethods[k] && k != "servers" {
+						out[k] = v
+					}
+				}
+				paths[outPath] = out
+			}
+			out[strings.ToLower(m)] = annotate(svc.Name, route, op)
+		}
+	}
+
+	specComponents, _ := spec["components"].(map[string]any)
+	for kind, entries := range specComponents {
+		if kind == "securitySchemes" {
+			continue
+		}
+		named, ok := entries.(map[string]any)
+		if !ok {
+			continue
+		}
+		dst, ok := components[kind].(map[string]any)
+		if !ok {
+			dst = make(map[string]any)
+			components[kind] = dst
+		}
+		for name, v := range named {
+			dst[svc.Name+"."+name] = v
+		}
+	}
+
+	return nil
+}
+
+// annotate replaces the operation security with the gateway policy of the
+// route, since the gateway is the one enforcing it.
+func annotate(service string, route config.RouteConfig, op map[string]any) map[string]any {
+	out := make(map[string]any, len(op)+2)
+	for k, v := range op {
+		out[k] = v
+	}
+
+	out["tags"] = []any{service}
+	if id, ok := op["operationId"].(string); ok {
+		out["operationId"] = service + "_" + id
+	}
+
+	if route.AuthRequired {
+		security := []any{map[string]any{"bearerAuth": []any{}}}
+		if len(route.Scopes) > 0 {
+			security = append(security, map[string]any{"apiKey": []any{}})
+			out["x-api-key-scopes"] = route.Scopes
+		}
+		out["security"] = security
+	} else {
+		out["security"] = []any{}
+	}
+
+	if len(route.Permissions) > 0 {
+		out["x-permissions"] = route.Permissions
+	}
+	if route.RateLimit != nil {
+		out["x-rate-limit"] = map[string]any{
+			"requests": route.RateLimit.Requests,
+			"per_sec":  route.RateLimit.PerSec,
+		}
+	}
+	return out
+}
+
+// normalize turns a decoded YAML value into JSON-encodable maps and prefixes
+// the local component references with the service name, so that components
+// of different services do not collide once merged.
+func normalize(v any, service string) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, child := range val {
+			out[k] = normalizeEntry(k, child, service)
+		}
+		return out
+	case map[any]any:
+		out := make(map[string]any, len(val))
+		for k, child := range val {
+			key := fmt.Sprint(k)
+			out[key] = normalizeEntry(key, child, service)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, child := range val {
+			out[i] = normalize(child, service)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func normalizeEntry(key string, v any, service string) any {
+	ref, ok := v.(string)
+	if key != "$ref" || !ok || !strings.HasPrefix(ref, "#/components/") {
+		return normalize(v, service)
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(ref, "#/components/"), "/", 2)
+	if len(parts) != 2 {
+		return ref
+	}
+	return "#/components/" + parts[0] + "/" + service + "." + parts[1]
+}
@@ -0,0 +1,16 @@
+package registry
+
+import "context"
+
+type routeContextKey struct{}
+
+// WithRoute stores the route matched for a request so the middlewares down the
+// chain don't resolve it again.
+func WithRoute(ctx context.Context, rt *RegisteredRoute) context.Context {
+	return context.WithValue(ctx, routeContextKey{}, rt)
+}
+
+func RouteFromContext(ctx context.Context) *RegisteredRoute {
+	rt, _ := ctx.Value(routeContextKey{}).(*RegisteredRoute)
+	return rt
+}
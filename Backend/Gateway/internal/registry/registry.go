@@ -2,9 +2,9 @@ package registry
 
 import (
 	"fmt"
-	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/raphael-guer1n/AREA/area-gateway/internal/config"
 )
@@ -12,17 +12,27 @@ import (
 type RegisteredRoute struct {
 	ServiceName    string
 	BaseURL        string
+	Backends       []string
+	LoadBalancing  string
 	Path           string
 	NamespacedPath string
 	Methods        []string
 	AuthRequired   bool
 	Permissions    []string
+	Scopes         []string
 	InternalOnly   bool
+	RateLimit      *config.RateLimitConfig
+	TimeoutMs      int
+	Streaming      bool
+	MaxBodyBytes   int
+	ContentTypes   []string
 }
 
 type Registry struct {
 	services map[string]config.ServiceConfig
 	routes   []RegisteredRoute
+	tree     *routeNode
+	loadedAt time.Time
 	mu       sync.RWMutex
 }
 
@@ -30,6 +40,7 @@ func NewRegistry() *Registry {
 	return &Registry{
 		services: make(map[string]config.ServiceConfig),
 		routes:   []RegisteredRoute{},
+		tree:     newRouteNode(),
 	}
 }
 
@@ -39,24 +50,75 @@ func (r *Registry) Load(services []config.ServiceConfig) error {
 
 	for _, svc := range services {
 		r.services[svc.Name] = svc
+		r.routes = append(r.routes, buildRoutes(svc)...)
+	}
+	r.tree = buildTree(r.routes)
+	r.loadedAt = time.Now()
+
+	return nil
+}
+
+// Replace swaps the whole registry content at once, so concurrent lookups see
+// either the previous or the new configuration, never a mix of both.
+func (r *Registry) Replace(services []config.ServiceConfig) error {
+	newServices := make(map[string]config.ServiceConfig, len(services))
+	newRoutes := []RegisteredRoute{}
 
-		for _, route := range svc.Routes {
-			r.routes = append(r.routes, RegisteredRoute{
-				ServiceName:    svc.Name,
-				BaseURL:        svc.BaseURL,
-				Path:           route.Path,
-				NamespacedPath: "/" + svc.Name + route.Path,
-				Methods:        route.Methods,
-				AuthRequired:   route.AuthRequired,
-				Permissions:    route.Permissions,
-				InternalOnly:   route.InternalOnly,
-			})
-		}
+	for _, svc := range services {
+		newServices[svc.Name] = svc
+		newRoutes = append(newRoutes, buildRoutes(svc)...)
 	}
+	newTree := buildTree(newRoutes)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.services = newServices
+	r.routes = newRoutes
+	r.tree = newTree
+	r.loadedAt = time.Now()
 
 	return nil
 }
 
+func buildRoutes(svc config.ServiceConfig) []RegisteredRoute {
+	backends := svc.Backends()
+	baseURL := ""
+	if len(backends) > 0 {
+		baseURL = backends[0]
+	}
+
+	routes := make([]RegisteredRoute, 0, len(svc.Routes))
+	for _, route := range svc.Routes {
+		routes = append(routes, RegisteredRoute{
+			ServiceName:    svc.Name,
+			BaseURL:        baseURL,
+			Backends:       backends,
+			LoadBalancing:  svc.LoadBalancing,
+			Path:           route.Path,
+			NamespacedPath: "/" + svc.Name + route.Path,
+			Methods:        route.Methods,
+			AuthRequired:   route.AuthRequired,
+			Permissions:    route.Permissions,
+			Scopes:         route.Scopes,
+			InternalOnly:   route.InternalOnly,
+			RateLimit:      route.RateLimit,
+			TimeoutMs:      route.TimeoutMs,
+			Streaming:      route.Streaming,
+			MaxBodyBytes:   route.MaxBodyBytes,
+			ContentTypes:   route.AllowedContentTypes,
+		})
+	}
+	return routes
+}
+
+func (r *Registry) LoadedAt() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.loadedAt
+}
+
 func (r *Registry) GetService(name string) (config.ServiceConfig, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -76,22 +138,15 @@ func (r *Registry) ListServices() []config.ServiceConfig {
 	return list
 }
 
+// FindRoute resolves a request path to the most specific route: at every
+// segment a literal beats a {param}, which beats a {name...} wildcard.
 func (r *Registry) FindRoute(path, method string) (*RegisteredRoute, error) {
 	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	for _, rt := range r.routes {
-		if method != "" && !slices.Contains(rt.Methods, method) {
-			continue
-		}
+	tree := r.tree
+	r.mu.RUnlock()
 
-		if pathMatchesPattern(path, rt.Path) {
-			return &rt, nil
-		}
-
-		if pathMatchesPattern(path, rt.NamespacedPath) {
-			return &rt, nil
-		}
+	if rt := tree.lookup(splitPathSegments(strings.Trim(path, "/")), method); rt != nil {
+		return rt, nil
 	}
 
 	return nil, fmt.Errorf("no route found for %s %s", method, path)
@@ -101,44 +156,6 @@ func (r *Registry) FindRouteByPath(path string) (*RegisteredRoute, error) {
 	return r.FindRoute(path, "")
 }
 
-func pathMatchesPattern(path, pattern string) bool {
-	if pattern == "" {
-		return false
-	}
-
-	if path == pattern {
-		return true
-	}
-
-	trimmedPath := strings.Trim(path, "/")
-	trimmedPattern := strings.Trim(pattern, "/")
-
-	if trimmedPath == trimmedPattern {
-		return true
-	}
-
-	pathSegments := splitPathSegments(trimmedPath)
-	patternSegments := splitPathSegments(trimmedPattern)
-
-	if len(pathSegments) != len(patternSegments) {
-		return false
-	}
-
-	for i, segment := range patternSegments {
-		if isParamSegment(segment) {
-			if pathSegments[i] == "" {
-				return false
-			}
-			continue
-		}
-		if segment != pathSegments[i] {
-			return false
-		}
-	}
-
-	return true
-}
-
 func splitPathSegments(path string) []string {
 	if path == "" {
 		return []string{}
@@ -0,0 +1,197 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/config"
+)
+
+func testService(name string, routes ...config.RouteConfig) config.ServiceConfig {
+	return config.ServiceConfig{Name: name, BaseURL: "http://" + name + ":8080", Routes: routes}
+}
+
+func testRoute(path string, methods ...string) config.RouteConfig {
+	return config.RouteConfig{Path: path, Methods: methods}
+}
+
+func loadTestRegistry(t *testing.T, services ...config.ServiceConfig) *Registry {
+	t.Helper()
+	if err := config.ValidateAll(services); err != nil {
+		t.Fatalf("invalid test config: %v", err)
+	}
+	reg := NewRegistry()
+	if err := reg.Replace(services); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	return reg
+}
+
+func TestFindRoute_Precedence(t *testing.T) {
+	reg := loadTestRegistry(t, testService("storage",
+		testRoute("/files/{path...}", "GET"),
+		testRoute("/files/{id}", "GET", "DELETE"),
+		testRoute("/files/latest", "GET"),
+		testRoute("/files/{id}/meta", "GET"),
+	))
+
+	testCases := []struct {
+		name    string
+		method  string
+		path    string
+		matched string
+	}{
+		{"static beats param", "GET", "/files/latest", "/files/latest"},
+		{"param beats wildcard", "GET", "/files/42", "/files/{id}"},
+		{"wildcard takes the rest", "GET", "/files/a/b/c", "/files/{path...}"},
+		{"deeper param route", "GET", "/files/42/meta", "/files/{id}/meta"},
+		{"backtracks from a static dead end", "GET", "/files/latest/meta", "/files/{id}/meta"},
+		{"method picks the param route", "DELETE", "/files/latest", "/files/{id}"},
+		{"namespaced static", "GET", "/storage/files/latest", "/files/latest"},
+		{"namespaced wildcard", "GET", "/storage/files/a/b", "/files/{path...}"},
+		{"unknown method", "POST", "/files/42", ""},
+		{"unknown path", "GET", "/folders/42", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rt, err := reg.FindRoute(tc.path, tc.method)
+			if tc.matched == "" {
+				if err == nil {
+					t.Fatalf("%s %s matched %s, want no route", tc.method, tc.path, rt.Path)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s %s: %v", tc.method, tc.path, err)
+			}
+			if rt.Path != tc.matched {
+				t.Fatalf("%s %s matched %s, want %s", tc.method, tc.path, rt.Path, tc.matched)
+			}
+		})
+	}
+}
+
+func TestFindRoute_SharedDirectPath(t *testing.T) {
+	reg := loadTestRegistry(t,
+		testService("area_auth_api", testRoute("/health", "GET"), testRoute("/auth/login", "POST")),
+		testService("area_area_api", testRoute("/health", "GET"), testRoute("/saveArea", "POST")),
+	)
+
+	testCases := []struct {
+		name    string
+		method  string
+		path    string
+		service string
+	}{
+		{"shared direct path is not exposed", "GET", "/health", ""},
+		{"first service under its prefix", "GET", "/area_auth_api/health", "area_auth_api"},
+		{"second service under its prefix", "GET", "/area_area_api/health", "area_area_api"},
+		{"unique direct path", "POST", "/auth/login", "area_auth_api"},
+		{"unique namespaced path", "POST", "/area_area_api/saveArea", "area_area_api"},
+		{"route of another service under a prefix", "POST", "/area_auth_api/saveArea", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rt, err := reg.FindRoute(tc.path, tc.method)
+			if tc.service == "" {
+				if err == nil {
+					t.Fatalf("%s %s matched %s of %s, want no route", tc.method, tc.path, rt.Path, rt.ServiceName)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s %s: %v", tc.method, tc.path, err)
+			}
+			if rt.ServiceName != tc.service {
+				t.Fatalf("%s %s matched service %s, want %s", tc.method, tc.path, rt.ServiceName, tc.service)
+			}
+		})
+	}
+}
+
+func TestFindRoute_NamespacedAndDirectPath(t *testing.T) {
+	reg := loadTestRegistry(t, testService("area_area_api", testRoute("/getAreas", "GET")))
+
+	direct, err := reg.FindRoute("/getAreas", "GET")
+	if err != nil {
+		t.Fatalf("direct path: %v", err)
+	}
+	namespaced, err := reg.FindRoute("/area_area_api/getAreas", "GET")
+	if err != nil {
+		t.Fatalf("namespaced path: %v", err)
+	}
+
+	if direct != namespaced {
+		t.Fatalf("direct and namespaced paths resolved to different routes")
+	}
+	if direct.Path != "/getAreas" {
+		t.Fatalf("Path = %s, want /getAreas", direct.Path)
+	}
+	if direct.NamespacedPath != "/area_area_api/getAreas" {
+		t.Fatalf("NamespacedPath = %s, want /area_area_api/getAreas", direct.NamespacedPath)
+	}
+}
+
+func TestValidateAll_AmbiguousRoutes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		services []config.ServiceConfig
+		valid    bool
+	}{
+		{
+			name: "direct path shared by two services",
+			services: []config.ServiceConfig{
+				testService("auth", testRoute("/health", "GET")),
+				testService("area", testRoute("/health", "GET")),
+			},
+			valid: true,
+		},
+		{
+			name: "direct path shadowing a namespaced path",
+			services: []config.ServiceConfig{
+				testService("auth", testRoute("/health", "GET")),
+				testService("area", testRoute("/auth/health", "GET")),
+			},
+		},
+		{
+			name: "same shape with other parameter names",
+			services: []config.ServiceConfig{
+				testService("auth", testRoute("/users/{id}", "GET")),
+				testService("area", testRoute("/auth/users/{userId}", "GET")),
+			},
+		},
+		{
+			name: "same shape on another method",
+			services: []config.ServiceConfig{
+				testService("auth", testRoute("/users/{id}", "GET")),
+				testService("area", testRoute("/auth/users/{userId}", "POST")),
+			},
+			valid: true,
+		},
+		{
+			name: "parameters differing only by name in one service",
+			services: []config.ServiceConfig{
+				testService("area", testRoute("/areas/{id}", "GET"), testRoute("/areas/{areaId}", "GET")),
+			},
+		},
+		{
+			name: "wildcard before the last segment",
+			services: []config.ServiceConfig{
+				testService("area", testRoute("/files/{path...}/meta", "GET")),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := config.ValidateAll(tc.services)
+			if tc.valid && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatalf("ambiguous config accepted")
+			}
+		})
+	}
+}
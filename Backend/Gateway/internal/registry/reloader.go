@@ -0,0 +1,208 @@
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/config"
+)
+
+// Reloader re-reads the service configs from disk and swaps them into the
+// registry. It is triggered by SIGHUP and, when an interval is set, by polling
+// the config files for changes.
+type Reloader struct {
+	rootDir     string
+	reg         *Registry
+	interval    time.Duration
+	stages      []func([]config.ServiceConfig) (func(), error)
+	onReload    []func()
+	fingerprint string
+	mu          sync.Mutex
+}
+
+func NewReloader(rootDir string, reg *Registry, interval time.Duration) *Reloader {
+	fp, err := configFingerprint(rootDir)
+	if err != nil {
+		log.Printf("[WARN] unable to fingerprint %s: %v", rootDir, err)
+	}
+	return &Reloader{
+		rootDir:     rootDir,
+		reg:         reg,
+		interval:    interval,
+		fingerprint: fp,
+	}
+}
+
+// Stage adds a step preparing what depends on the new configs, run once they
+// are valid. An error aborts the reload; otherwise the returned function is
+// called once the registry holds the new configs, so that nothing built from
+// them is published by a reload that does not go through.
+func (rl *Reloader) Stage(fn func([]config.ServiceConfig) (func(), error)) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.stages = append(rl.stages, fn)
+}
+
+func (rl *Reloader) OnReload(fn func()) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.onReload = append(rl.onReload, fn)
+}
+
+func (rl *Reloader) Start() {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	var tick <-chan time.Time
+	if rl.interval > 0 {
+		ticker := time.NewTicker(rl.interval)
+		tick = ticker.C
+	}
+
+	go func() {
+		for {
+			select {
+			case <-sighup:
+				log.Println("[INFO] SIGHUP received, reloading service configs")
+				if err := rl.Reload(); err != nil {
+					log.Printf("[ERROR] service config reload failed: %v", err)
+				}
+			case <-tick:
+				fp, err := configFingerprint(rl.rootDir)
+				if err != nil {
+					log.Printf("[WARN] unable to fingerprint %s: %v", rl.rootDir, err)
+					continue
+				}
+				rl.mu.Lock()
+				changed := fp != rl.fingerprint
+				rl.mu.Unlock()
+				if !changed {
+					continue
+				}
+				log.Println("[INFO] service configs changed on disk, reloading")
+				if err := rl.Reload(); err != nil {
+					log.Printf("[ERROR] service config reload failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Reload keeps the current configuration untouched when the new one fails to
+// load or validate.
+func (rl *Reloader) Reload() error {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	fp, err := configFingerprint(rl.rootDir)
+	if err != nil {
+		return err
+	}
+
+	services, err := config.LoadAllServiceConfigs(rl.rootDir)
+	if err != nil {
+		rl.fingerprint = fp
+		return fmt.Errorf("failed to load service configs: %w", err)
+	}
+
+	if err := config.ValidateAll(services); err != nil {
+		rl.fingerprint = fp
+		return fmt.Errorf("invalid service configuration: %w", err)
+	}
+
+	publish := make([]func(), 0, len(rl.stages))
+	for _, stage := range rl.stages {
+		commit, err := stage(services)
+		if err != nil {
+			rl.fingerprint = fp
+			return fmt.Errorf("invalid service configuration: %w", err)
+		}
+		publish = append(publish, commit)
+	}
+
+	if err := rl.reg.Replace(services); err != nil {
+		return err
+	}
+	rl.fingerprint = fp
+
+	for _, commit := range publish {
+		commit()
+	}
+
+	for _, fn := range rl.onReload {
+		fn()
+	}
+
+	log.Printf("[INFO] Reloaded %d services, %d routes", len(services), len(rl.reg.ListAllRoutes()))
+	return nil
+}
+
+// configFingerprint covers the service configs and the openapi specs they
+// point to, so that editing a spec reloads the gateway document too.
+func configFingerprint(rootDir string) (string, error) {
+	var entries []string
+
+	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && info.Name() == "service.config.json" {
+			entries = append(entries, fileEntry(path, info))
+			if spec := specPath(path); spec != "" {
+				specInfo, err := os.Stat(spec)
+				if err != nil {
+					entries = append(entries, spec+"|missing")
+				} else {
+					entries = append(entries, fileEntry(spec, specInfo))
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sort.Strings(entries)
+	h := sha256.New()
+	for _, e := range entries {
+		h.Write([]byte(e))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func fileEntry(path string, info os.FileInfo) string {
+	return fmt.Sprintf("%s|%d|%d", path, info.Size(), info.ModTime().UnixNano())
+}
+
+// specPath returns the openapi spec of a service config, resolved like the
+// loader does. A config that cannot be read is left to the reload to report.
+func specPath(configPath string) string {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return ""
+	}
+	var cfg struct {
+		OpenAPI string `json:"openapi"`
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil || cfg.OpenAPI == "" {
+		return ""
+	}
+	if filepath.IsAbs(cfg.OpenAPI) {
+		return cfg.OpenAPI
+	}
+	return filepath.Join(filepath.Dir(configPath), cfg.OpenAPI)
+}
@@ -0,0 +1,110 @@
+package registry
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/config"
+)
+
+func writeServiceConfig(t *testing.T, dir, body string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "service.config.json"), []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloader_PublishesStagesAfterReplace(t *testing.T) {
+	root := t.TempDir()
+	writeServiceConfig(t, filepath.Join(root, "area"), `{
+		"name": "area",
+		"base_url": "http://area:8080",
+		"routes": [{"path": "/getAreas", "methods": ["GET"]}]
+	}`)
+
+	reg := NewRegistry()
+	rl := NewReloader(root, reg, 0)
+	published := false
+	rl.Stage(func(services []config.ServiceConfig) (func(), error) {
+		return func() {
+			if _, err := reg.FindRoute("/getAreas", "GET"); err != nil {
+				t.Errorf("stage published before the registry swap: %v", err)
+			}
+			published = true
+		}, nil
+	})
+
+	if err := rl.Reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if !published {
+		t.Fatal("stage of a successful reload was not published")
+	}
+}
+
+func TestReloader_FailedReloadPublishesNothing(t *testing.T) {
+	root := t.TempDir()
+	writeServiceConfig(t, filepath.Join(root, "area"), `{
+		"name": "area",
+		"base_url": "http://area:8080",
+		"routes": [{"path": "/getAreas", "methods": ["GET"]}]
+	}`)
+
+	reg := NewRegistry()
+	rl := NewReloader(root, reg, 0)
+	published := false
+	rl.Stage(func([]config.ServiceConfig) (func(), error) {
+		return func() { published = true }, nil
+	})
+	rl.Stage(func([]config.ServiceConfig) (func(), error) {
+		return nil, errors.New("route missing from the spec")
+	})
+
+	if err := rl.Reload(); err == nil {
+		t.Fatal("reload with a failing stage succeeded")
+	}
+	if published {
+		t.Fatal("stage published by a failed reload")
+	}
+	if len(reg.ListAllRoutes()) != 0 {
+		t.Fatal("registry replaced by a failed reload")
+	}
+}
+
+func TestConfigFingerprint_CoversOpenAPISpec(t *testing.T) {
+	root := t.TempDir()
+	writeServiceConfig(t, filepath.Join(root, "area"), `{
+		"name": "area",
+		"base_url": "http://area:8080",
+		"openapi": "../specs/area.yaml",
+		"routes": [{"path": "/getAreas", "methods": ["GET"]}]
+	}`)
+	spec := filepath.Join(root, "specs", "area.yaml")
+	if err := os.MkdirAll(filepath.Dir(spec), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(spec, []byte("openapi: 3.1.0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := configFingerprint(root)
+	if err != nil {
+		t.Fatalf("fingerprint: %v", err)
+	}
+	if err := os.WriteFile(spec, []byte("openapi: 3.1.0\npaths: {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	after, err := configFingerprint(root)
+	if err != nil {
+		t.Fatalf("fingerprint: %v", err)
+	}
+
+	if before == after {
+		t.Fatal("editing the openapi spec left the fingerprint unchanged")
+	}
+}
@@ -0,0 +1,138 @@
+package registry
+
+import (
+	"log"
+	"strings"
+)
+
+// routeNode is one path segment of the route tree. A lookup tries, at each
+// segment, the static child first, then the parameter child, then the
+// wildcard, and backtracks when a branch cannot complete the match.
+type routeNode struct {
+	static   map[string]*routeNode
+	param    *routeNode
+	wildcard *routeNode
+	routes   map[string]*RegisteredRoute
+}
+
+func newRouteNode() *routeNode {
+	return &routeNode{
+		static: make(map[string]*routeNode),
+		routes: make(map[string]*RegisteredRoute),
+	}
+}
+
+func isWildcardSegment(segment string) bool {
+	return isParamSegment(segment) && strings.HasSuffix(segment, "...}")
+}
+
+func (n *routeNode) insert(pattern string) *routeNode {
+	node := n
+	for _, segment := range splitPathSegments(strings.Trim(pattern, "/")) {
+		switch {
+		case isWildcardSegment(segment):
+			if node.wildcard == nil {
+				node.wildcard = newRouteNode()
+			}
+			// a wildcard consumes the rest of the path
+			return node.wildcard
+		case isParamSegment(segment):
+			if node.param == nil {
+				node.param = newRouteNode()
+			}
+			node = node.param
+		default:
+			child, ok := node.static[segment]
+			if !ok {
+				child = newRouteNode()
+				node.static[segment] = child
+			}
+			node = child
+		}
+	}
+	return node
+}
+
+// lookup returns the most specific route matching the segments. With an empty
+// method any route of the matching node is returned.
+func (n *routeNode) lookup(segments []string, method string) *RegisteredRoute {
+	if len(segments) == 0 {
+		return n.route(method)
+	}
+
+	if child, ok := n.static[segments[0]]; ok {
+		if rt := child.lookup(segments[1:], method); rt != nil {
+			return rt
+		}
+	}
+	if n.param != nil && segments[0] != "" {
+		if rt := n.param.lookup(segments[1:], method); rt != nil {
+			return rt
+		}
+	}
+	if n.wildcard != nil {
+		return n.wildcard.route(method)
+	}
+	return nil
+}
+
+func (n *routeNode) route(method string) *RegisteredRoute {
+	if method != "" {
+		return n.routes[method]
+	}
+	for _, rt := range n.routes {
+		return rt
+	}
+	return nil
+}
+
+type routeOwner struct {
+	node  *routeNode
+	route *RegisteredRoute
+}
+
+// buildTree mounts every route under its namespaced path, and under its direct
+// path only when no other route claims the same pattern and method.
+func buildTree(routes []RegisteredRoute) *routeNode {
+	root := newRouteNode()
+
+	for i := range routes {
+		rt := &routes[i]
+		node := root.insert(rt.NamespacedPath)
+		for _, m := range rt.Methods {
+			node.routes[m] = rt
+		}
+	}
+
+	direct := make(map[*routeNode]map[string][]*RegisteredRoute)
+	var order []routeOwner
+	for i := range routes {
+		rt := &routes[i]
+		node := root.insert(rt.Path)
+		if direct[node] == nil {
+			direct[node] = make(map[string][]*RegisteredRoute)
+		}
+		for _, m := range rt.Methods {
+			direct[node][m] = append(direct[node][m], rt)
+		}
+		order = append(order, routeOwner{node: node, route: rt})
+	}
+
+	for _, owner := range order {
+		for _, m := range owner.route.Methods {
+			claims := direct[owner.node][m]
+			if _, taken := owner.node.routes[m]; taken {
+				continue
+			}
+			if len(claims) > 1 {
+				if claims[0] == owner.route {
+					log.Printf("[INFO] %s %s is exposed by %d services, only reachable under their prefix", m, owner.route.Path, len(claims))
+				}
+				continue
+			}
+			owner.node.routes[m] = owner.route
+		}
+	}
+
+	return root
+}
@@ -0,0 +1,198 @@
+package revocation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// syncOverlap is subtracted from the server time of the previous sync, so that
+// entries committed while it was running are fetched by the next one.
+const syncOverlap = 10 * time.Second
+
+type entry struct {
+	JTI          string     `json:"jti"`
+	UserID       int        `json:"user_id"`
+	IssuedBefore *time.Time `json:"issued_before"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+}
+
+// userCutoff revokes the tokens of a user issued before issuedBefore, to the
+// millisecond: a token issued in the same millisecond as the revocation, e.g.
+// on a login right after it, stays valid.
+type userCutoff struct {
+	issuedBefore time.Time
+	expiresAt    time.Time
+}
+
+// DenyList caches the revoked tokens published by AuthService. The first sync
+// fetches the whole list and the following ones only what was revoked since,
+// so a revoked token is refused at most about `interval` after its revocation.
+// When AuthService cannot be reached the cached list keeps being enforced.
+type DenyList struct {
+	url            string
+	internalSecret string
+	interval       time.Duration
+	client         *http.Client
+
+	jtis   map[string]time.Time
+	users  map[string]userCutoff
+	since  int64
+	synced bool
+	mu     sync.RWMutex
+	syncMu sync.Mutex
+}
+
+func NewDenyList(url, internalSecret string, interval time.Duration) *DenyList {
+	return &DenyList{
+		url:            url,
+		internalSecret: internalSecret,
+		interval:       interval,
+		client:         &http.Client{Timeout: 5 * time.Second},
+		jtis:           make(map[string]time.Time),
+		users:          make(map[string]userCutoff),
+	}
+}
+
+func (d *DenyList) Start() {
+	if err := d.Sync(); err != nil {
+		log.Printf("[WARN] initial token revocation sync from %s failed: %v", d.url, err)
+	}
+	if d.interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := d.Sync(); err != nil {
+				log.Printf("[WARN] token revocation sync from %s failed, keeping cached list: %v", d.url, err)
+			}
+		}
+	}()
+}
+
+// IsRevoked reports whether the token with the given jti, subject and issue
+// time has been revoked. A zero issuedAt is covered by every user cutoff.
+func (d *DenyList) IsRevoked(jti, sub string, issuedAt time.Time) bool {
+	now := time.Now()
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if jti != "" {
+		if expiresAt, ok := d.jtis[jti]; ok && now.Before(expiresAt) {
+			return true
+		}
+	}
+	if cutoff, ok := d.users[sub]; ok && now.Before(cutoff.expiresAt) {
+		return issuedAt.Truncate(time.Millisecond).Before(cutoff.issuedBefore)
+	}
+	return false
+}
+
+func (d *DenyList) Sync() error {
+	d.syncMu.Lock()
+	defer d.syncMu.Unlock()
+
+	d.mu.RLock()
+	since, synced := d.since, d.synced
+	d.mu.RUnlock()
+
+	entries, serverTime, err := d.fetch(since, synced)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, e := range entries {
+		if !now.Before(e.ExpiresAt) {
+			continue
+		}
+		if e.JTI != "" {
+			d.jtis[e.JTI] = e.ExpiresAt
+			continue
+		}
+		if e.IssuedBefore == nil {
+			continue
+		}
+		sub := strconv.Itoa(e.UserID)
+		cutoff := userCutoff{issuedBefore: e.IssuedBefore.Truncate(time.Millisecond), expiresAt: e.ExpiresAt}
+		if old, ok := d.users[sub]; ok {
+			// several entries of a user collapse into the widest one
+			if old.issuedBefore.After(cutoff.issuedBefore) {
+				cutoff.issuedBefore = old.issuedBefore
+			}
+			if old.expiresAt.After(cutoff.expiresAt) {
+				cutoff.expiresAt = old.expiresAt
+			}
+		}
+		d.users[sub] = cutoff
+	}
+
+	for jti, expiresAt := range d.jtis {
+		if !now.Before(expiresAt) {
+			delete(d.jtis, jti)
+		}
+	}
+	for sub, cutoff := range d.users {
+		if !now.Before(cutoff.expiresAt) {
+			delete(d.users, sub)
+		}
+	}
+
+	d.since = serverTime - int64(syncOverlap/time.Second)
+	d.synced = true
+	return nil
+}
+
+func (d *DenyList) fetch(since int64, incremental bool) ([]entry, int64, error) {
+	target := d.url
+	if incremental && since > 0 {
+		u, err := url.Parse(d.url)
+		if err != nil {
+			return nil, 0, err
+		}
+		q := u.Query()
+		q.Set("since", strconv.FormatInt(since, 10))
+		u.RawQuery = q.Encode()
+		target = u.String()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("X-Internal-Secret", d.internalSecret)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("revocation endpoint returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data struct {
+			Revocations []entry `json:"revocations"`
+			ServerTime  int64   `json:"server_time"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 16<<20)).Decode(&body); err != nil {
+		return nil, 0, fmt.Errorf("invalid revocation list: %w", err)
+	}
+	return body.Data.Revocations, body.Data.ServerTime, nil
+}
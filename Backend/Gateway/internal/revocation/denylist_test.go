@@ -0,0 +1,184 @@
+package revocation
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAuthService serves the revocation list with `since` filtering, like
+// AuthService does.
+type fakeAuthService struct {
+	mu         sync.Mutex
+	entries    []map[string]any
+	revokedAt  []int64
+	serverTime int64
+	down       bool
+	queries    []string
+}
+
+func (f *fakeAuthService) revoke(revokedAt int64, e map[string]any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.entries = append(f.entries, e)
+	f.revokedAt = append(f.revokedAt, revokedAt)
+}
+
+func (f *fakeAuthService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.queries = append(f.queries, r.URL.RawQuery)
+	if f.down || r.Header.Get("X-Internal-Secret") != "secret" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	var since int64
+	if raw := r.URL.Query().Get("since"); raw != "" {
+		since, _ = strconv.ParseInt(raw, 10, 64)
+	}
+	revocations := []map[string]any{}
+	for i, e := range f.entries {
+		if f.revokedAt[i] > since {
+			revocations = append(revocations, e)
+		}
+	}
+	json.NewEncoder(w).Encode(map[string]any{
+		"success": true,
+		"data":    map[string]any{"revocations": revocations, "server_time": f.serverTime},
+	})
+}
+
+func newTestDenyList(t *testing.T) (*DenyList, *fakeAuthService) {
+	t.Helper()
+	fake := &fakeAuthService{serverTime: time.Now().Unix()}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return NewDenyList(server.URL, "secret", 0), fake
+}
+
+func TestDenyList_IncrementalSync(t *testing.T) {
+	d, fake := newTestDenyList(t)
+	expiresAt := time.Now().Add(time.Hour)
+
+	fake.revoke(fake.serverTime-60, map[string]any{"jti": "a", "user_id": 1, "expires_at": expiresAt})
+	if err := d.Sync(); err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+
+	fake.serverTime += 30
+	fake.revoke(fake.serverTime-5, map[string]any{"jti": "b", "user_id": 2, "expires_at": expiresAt})
+	if err := d.Sync(); err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+
+	if fake.queries[0] != "" {
+		t.Fatalf("first sync asked for %q, want the whole list", fake.queries[0])
+	}
+	wantSince := "since=" + strconv.FormatInt(fake.serverTime-30-int64(syncOverlap/time.Second), 10)
+	if fake.queries[1] != wantSince {
+		t.Fatalf("second sync asked for %q, want %q", fake.queries[1], wantSince)
+	}
+	if !d.IsRevoked("a", "1", time.Now()) {
+		t.Fatal("entry of the first sync was dropped by the second one")
+	}
+	if !d.IsRevoked("b", "2", time.Now()) {
+		t.Fatal("entry of the second sync is missing")
+	}
+	if d.IsRevoked("c", "3", time.Now()) {
+		t.Fatal("token never revoked is refused")
+	}
+}
+
+func TestDenyList_UserCutoff(t *testing.T) {
+	d, fake := newTestDenyList(t)
+	revokedAt := time.Date(2026, 10, 17, 12, 0, 0, 400_123_000, time.UTC)
+	fake.revoke(fake.serverTime, map[string]any{
+		"user_id":       42,
+		"issued_before": revokedAt,
+		"expires_at":    time.Now().Add(time.Hour),
+	})
+	if err := d.Sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		sub      string
+		issuedAt time.Time
+		revoked  bool
+	}{
+		{"issued earlier in the same second", "42", revokedAt.Add(-300 * time.Millisecond), true},
+		{"issued the millisecond before", "42", revokedAt.Add(-time.Millisecond), true},
+		{"issued in the same millisecond", "42", revokedAt.Truncate(time.Millisecond), false},
+		{"issued later in the same second", "42", revokedAt.Add(300 * time.Millisecond), false},
+		{"without issue time", "42", time.Time{}, true},
+		{"other user", "43", revokedAt.Add(-time.Second), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := d.IsRevoked("", tc.sub, tc.issuedAt); got != tc.revoked {
+				t.Fatalf("IsRevoked = %v, want %v", got, tc.revoked)
+			}
+		})
+	}
+}
+
+func TestDenyList_WidestUserCutoff(t *testing.T) {
+	d, fake := newTestDenyList(t)
+	first := time.Now().Add(-time.Hour)
+	second := time.Now().Add(-time.Minute)
+
+	fake.revoke(fake.serverTime, map[string]any{"user_id": 42, "issued_before": second, "expires_at": time.Now().Add(time.Hour)})
+	if err := d.Sync(); err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	fake.serverTime += 30
+	fake.revoke(fake.serverTime, map[string]any{"user_id": 42, "issued_before": first, "expires_at": time.Now().Add(2 * time.Hour)})
+	if err := d.Sync(); err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+
+	if !d.IsRevoked("", "42", second.Add(-time.Second)) {
+		t.Fatal("an older cutoff narrowed the revocation of the user")
+	}
+}
+
+func TestDenyList_KeepsCacheWhenAuthServiceIsDown(t *testing.T) {
+	d, fake := newTestDenyList(t)
+	fake.revoke(fake.serverTime, map[string]any{"jti": "a", "user_id": 1, "expires_at": time.Now().Add(time.Hour)})
+	if err := d.Sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+
+	fake.down = true
+	if err := d.Sync(); err == nil {
+		t.Fatal("sync against an unavailable AuthService succeeded")
+	}
+
+	if !d.IsRevoked("a", "1", time.Now()) {
+		t.Fatal("cached entry dropped after a failed sync")
+	}
+}
+
+func TestDenyList_DropsExpiredEntries(t *testing.T) {
+	d, fake := newTestDenyList(t)
+	fake.revoke(fake.serverTime, map[string]any{"jti": "old", "user_id": 1, "expires_at": time.Now().Add(-time.Minute)})
+	fake.revoke(fake.serverTime, map[string]any{"user_id": 2, "issued_before": time.Now(), "expires_at": time.Now().Add(-time.Minute)})
+	if err := d.Sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+
+	if d.IsRevoked("old", "1", time.Now()) || d.IsRevoked("", "2", time.Now().Add(-time.Hour)) {
+		t.Fatal("expired entry still enforced")
+	}
+	if len(d.jtis) != 0 || len(d.users) != 0 {
+		t.Fatalf("expired entries kept: %d jtis, %d users", len(d.jtis), len(d.users))
+	}
+}
@@ -0,0 +1,281 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/config"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/core"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/health"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/registry"
+)
+
+// Admin endpoints are internal-only (see Build) and describe what the gateway
+// has loaded, so routing issues can be debugged without reading the configs.
+
+type adminService struct {
+	Name          string                `json:"name"`
+	LoadBalancing string                `json:"load_balancing"`
+	Routes        int                   `json:"routes"`
+	Backends      []BackendStatus       `json:"backends"`
+	Health        *health.ServiceStatus `json:"health,omitempty"`
+}
+
+type adminRoute struct {
+	Service             string                  `json:"service"`
+	Path                string                  `json:"path"`
+	NamespacedPath      string                  `json:"namespaced_path"`
+	Methods             []string                `json:"methods"`
+	AuthRequired        bool                    `json:"auth_required"`
+	Permissions         []string                `json:"permissions"`
+	Scopes              []string                `json:"scopes,omitempty"`
+	InternalOnly        bool                    `json:"internal_only"`
+	RateLimit           *config.RateLimitConfig `json:"rate_limit,omitempty"`
+	TimeoutMs           int                     `json:"timeout_ms,omitempty"`
+	Streaming           bool                    `json:"streaming,omitempty"`
+	MaxBodyBytes        int                     `json:"max_body_bytes,omitempty"`
+	AllowedContentTypes []string                `json:"allowed_content_types,omitempty"`
+}
+
+type adminMiddleware struct {
+	Name   string `json:"name"`
+	Detail string `json:"detail,omitempty"`
+}
+
+func newAdminRoute(rt *registry.RegisteredRoute) adminRoute {
+	perms := rt.Permissions
+	if perms == nil {
+		perms = []string{}
+	}
+	return adminRoute{
+		Service:             rt.ServiceName,
+		Path:                rt.Path,
+		NamespacedPath:      rt.NamespacedPath,
+		Methods:             rt.Methods,
+		AuthRequired:        rt.AuthRequired,
+		Permissions:         perms,
+		Scopes:              rt.Scopes,
+		InternalOnly:        rt.InternalOnly,
+		RateLimit:           rt.RateLimit,
+		TimeoutMs:           rt.TimeoutMs,
+		Streaming:           rt.Streaming,
+		MaxBodyBytes:        rt.MaxBodyBytes,
+		AllowedContentTypes: rt.ContentTypes,
+	}
+}
+
+func writeAdminJSON(w http.ResponseWriter, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func adminMethodAllowed(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method == http.MethodGet {
+		return true
+	}
+	core.WriteError(w, http.StatusMethodNotAllowed, core.ErrForbidden, "Method not allowed")
+	return false
+}
+
+func (rt *Router) adminServices(w http.ResponseWriter, r *http.Request) {
+	if !adminMethodAllowed(w, r) {
+		return
+	}
+
+	statuses := make(map[string]health.ServiceStatus)
+	for _, s := range rt.health.Statuses() {
+		statuses[s.Name] = s
+	}
+
+	routeCount := make(map[string]int)
+	for _, route := range rt.registry.ListAllRoutes() {
+		routeCount[route.ServiceName]++
+	}
+
+	rt.proxiesMu.Lock()
+	pools := make(map[string]*BackendPool, len(rt.proxies))
+	for name, entry := range rt.proxies {
+		pools[name] = entry.pool
+	}
+	rt.proxiesMu.Unlock()
+
+	services := []adminService{}
+	for _, svc := range rt.registry.ListServices() {
+		item := adminService{
+			Name:          svc.Name,
+			LoadBalancing: svc.LoadBalancing,
+			Routes:        routeCount[svc.Name],
+		}
+		if item.LoadBalancing == "" {
+			item.LoadBalancing = config.LoadBalancingRoundRobin
+		}
+		if pool := pools[svc.Name]; pool != nil {
+			item.Backends = pool.Snapshot()
+		} else {
+			// no proxy built yet, e.g. the backend url is invalid
+			for _, backend := range svc.Backends() {
+				item.Backends = append(item.Backends, BackendStatus{URL: backend})
+			}
+		}
+		if status, ok := statuses[svc.Name]; ok {
+			item.Health = &status
+		}
+		services = append(services, item)
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+
+	writeAdminJSON(w, map[string]any{
+		"loaded_at": rt.registry.LoadedAt().Format(time.RFC3339),
+		"services":  services,
+	})
+}
+
+func (rt *Router) adminRoutes(w http.ResponseWriter, r *http.Request) {
+	if !adminMethodAllowed(w, r) {
+		return
+	}
+
+	all := rt.registry.ListAllRoutes()
+	routes := make([]adminRoute, 0, len(all))
+	for i := range all {
+		routes = append(routes, newAdminRoute(&all[i]))
+	}
+	sort.SliceStable(routes, func(i, j int) bool { return routes[i].NamespacedPath < routes[j].NamespacedPath })
+
+	writeAdminJSON(w, map[string]any{
+		"loaded_at": rt.registry.LoadedAt().Format(time.RFC3339),
+		"routes":    routes,
+	})
+}
+
+// adminResolve reports the route a request would match, the path forwarded
+// upstream and the middlewares acting on it, in execution order.
+func (rt *Router) adminResolve(w http.ResponseWriter, r *http.Request) {
+	if !adminMethodAllowed(w, r) {
+		return
+	}
+
+	method := strings.ToUpper(r.URL.Query().Get("method"))
+	path := r.URL.Query().Get("path")
+	if method == "" || !strings.HasPrefix(path, "/") {
+		core.WriteError(w, http.StatusBadRequest, core.ErrBadRequest, "Query parameters 'method' and 'path' (starting with '/') are required")
+		return
+	}
+
+	route, err := rt.registry.FindRoute(path, method)
+	if err != nil {
+		result := map[string]any{
+			"method":  method,
+			"path":    path,
+			"matched": false,
+		}
+		if other, pathErr := rt.registry.FindRouteByPath(path); pathErr == nil {
+			result["reason"] = fmt.Sprintf("path matches %s but not for %s (405)", other.NamespacedPath, method)
+		} else {
+			result["reason"] = "no route matches the path (404)"
+		}
+		writeAdminJSON(w, result)
+		return
+	}
+
+	prefix := "/" + route.ServiceName
+	namespaced := path == prefix || strings.HasPrefix(path, prefix+"/")
+	upstreamPath := path
+	if namespaced {
+		upstreamPath = strings.TrimPrefix(path, prefix)
+		if upstreamPath == "" {
+			upstreamPath = "/"
+		}
+	}
+
+	writeAdminJSON(w, map[string]any{
+		"method":        method,
+		"path":          path,
+		"matched":       true,
+		"namespaced":    namespaced,
+		"route":         newAdminRoute(route),
+		"upstream_path": upstreamPath,
+		"middlewares":   rt.middlewaresFor(route),
+	})
+}
+
+// middlewaresFor mirrors the chain built in Build and keeps the middlewares
+// that act on the route.
+func (rt *Router) middlewaresFor(route *registry.RegisteredRoute) []adminMiddleware {
+	chain := []adminMiddleware{
+		{Name: "metrics"},
+		{Name: "logging"},
+	}
+
+	if route.InternalOnly {
+		chain = append(chain, adminMiddleware{Name: "internal", Detail: "requires X-Internal-Secret"})
+	}
+
+	maxBody := route.MaxBodyBytes
+	if maxBody == 0 {
+		maxBody = rt.config.MaxBodyBytes
+	}
+	contentTypes := route.ContentTypes
+	if len(contentTypes) == 0 {
+		contentTypes = rt.config.AllowedContentTypes
+	}
+	var bodyRules []string
+	if maxBody > 0 {
+		bodyRules = append(bodyRules, fmt.Sprintf("max %d bytes", maxBody))
+	}
+	if len(contentTypes) > 0 {
+		bodyRules = append(bodyRules, "content types "+strings.Join(contentTypes, ", "))
+	}
+	if len(bodyRules) > 0 {
+		chain = append(chain, adminMiddleware{Name: "body", Detail: strings.Join(bodyRules, "; ")})
+	}
+
+	if route.AuthRequired {
+		detail := "Bearer JWT"
+		if rt.config.ApiKeyVerifyURL != "" && len(route.Scopes) > 0 {
+			detail += " or ApiKey"
+		}
+		if rt.config.TokenRevocationURL != "" {
+			detail += ", revoked tokens refused"
+		}
+		chain = append(chain, adminMiddleware{Name: "auth", Detail: detail})
+	}
+	if route.RateLimit != nil {
+		chain = append(chain, adminMiddleware{
+			Name:   "rate_limit",
+			Detail: fmt.Sprintf("%d requests per %ds", route.RateLimit.Requests, route.RateLimit.PerSec),
+		})
+	}
+	if len(route.Scopes) > 0 {
+		chain = append(chain, adminMiddleware{Name: "api_key_scopes", Detail: strings.Join(route.Scopes, ", ")})
+	}
+	if len(route.Permissions) > 0 {
+		chain = append(chain, adminMiddleware{Name: "permissions", Detail: strings.Join(route.Permissions, ", ")})
+	}
+
+	if route.AuthRequired {
+		chain = append(chain, adminMiddleware{Name: "identity", Detail: "strips and signs X-User-* headers"})
+	} else {
+		chain = append(chain, adminMiddleware{Name: "identity", Detail: "strips X-User-* headers"})
+	}
+
+	chain = append(chain, adminMiddleware{Name: "circuit_breaker", Detail: route.ServiceName})
+
+	if route.Streaming {
+		chain = append(chain, adminMiddleware{Name: "proxy", Detail: "streaming, no timeout"})
+		return chain
+	}
+	timeoutMs := route.TimeoutMs
+	if timeoutMs <= 0 {
+		timeoutMs = rt.config.RequestTimeoutMs
+	}
+	if timeoutMs > 0 {
+		chain = append(chain, adminMiddleware{Name: "timeout", Detail: fmt.Sprintf("%dms", timeoutMs)})
+	}
+	return append(chain, adminMiddleware{Name: "proxy"})
+}
@@ -0,0 +1,182 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/config"
+)
+
+type backendContextKey struct{}
+
+type Backend struct {
+	URL *url.URL
+
+	active       int
+	failures     int
+	ejectedUntil time.Time
+}
+
+// BackendPool spreads the traffic of one service across its instances and
+// passively ejects the ones that keep failing.
+type BackendPool struct {
+	backends       []*Backend
+	strategy       string
+	ejectThreshold int
+	ejectDuration  time.Duration
+	next           int
+	now            func() time.Time
+	mu             sync.Mutex
+}
+
+func NewBackendPool(baseURLs []string, strategy string, ejectThreshold int, ejectDuration time.Duration) (*BackendPool, error) {
+	if len(baseURLs) == 0 {
+		return nil, fmt.Errorf("no backend configured")
+	}
+	if strategy == "" {
+		strategy = config.LoadBalancingRoundRobin
+	}
+
+	pool := &BackendPool{
+		strategy:       strategy,
+		ejectThreshold: ejectThreshold,
+		ejectDuration:  ejectDuration,
+		now:            time.Now,
+	}
+	for _, raw := range baseURLs {
+		u, err := url.Parse(raw)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid backend url: %s", raw)
+		}
+		pool.backends = append(pool.backends, &Backend{URL: u})
+	}
+	return pool, nil
+}
+
+func poolSignature(baseURLs []string, strategy string) string {
+	return strategy + "|" + strings.Join(baseURLs, ",")
+}
+
+// Acquire picks a backend and counts it as busy until Release is called. When
+// every backend is ejected, the one whose ejection ends first is used anyway.
+func (p *BackendPool) Acquire() *Backend {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	var candidates []*Backend
+	for _, b := range p.backends {
+		if !now.Before(b.ejectedUntil) {
+			candidates = append(candidates, b)
+		}
+	}
+	if len(candidates) == 0 {
+		soonest := p.backends[0]
+		for _, b := range p.backends[1:] {
+			if b.ejectedUntil.Before(soonest.ejectedUntil) {
+				soonest = b
+			}
+		}
+		candidates = []*Backend{soonest}
+	}
+
+	var chosen *Backend
+	if p.strategy == config.LoadBalancingLeastConnections {
+		start := p.next % len(candidates)
+		for i := range candidates {
+			b := candidates[(start+i)%len(candidates)]
+			if chosen == nil || b.active < chosen.active {
+				chosen = b
+			}
+		}
+	} else {
+		chosen = candidates[p.next%len(candidates)]
+	}
+	p.next++
+
+	chosen.active++
+	return chosen
+}
+
+func (p *BackendPool) Release(b *Backend) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if b.active > 0 {
+		b.active--
+	}
+}
+
+type BackendStatus struct {
+	URL          string     `json:"url"`
+	Active       int        `json:"active"`
+	Failures     int        `json:"failures"`
+	Ejected      bool       `json:"ejected"`
+	EjectedUntil *time.Time `json:"ejected_until,omitempty"`
+}
+
+func (p *BackendPool) Snapshot() []BackendStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	statuses := make([]BackendStatus, 0, len(p.backends))
+	for _, b := range p.backends {
+		status := BackendStatus{
+			URL:      b.URL.String(),
+			Active:   b.active,
+			Failures: b.failures,
+			Ejected:  now.Before(b.ejectedUntil),
+		}
+		if status.Ejected {
+			until := b.ejectedUntil
+			status.EjectedUntil = &until
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func (p *BackendPool) MarkSuccess(b *Backend) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	b.failures = 0
+	b.ejectedUntil = time.Time{}
+}
+
+// MarkFailure reports whether every backend of the pool is now ejected.
+func (p *BackendPool) MarkFailure(b *Backend) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	b.failures++
+	if p.ejectThreshold > 0 && b.failures >= p.ejectThreshold && !now.Before(b.ejectedUntil) {
+		b.ejectedUntil = now.Add(p.ejectDuration)
+		// a single failure after re-admission ejects the backend again
+		b.failures = p.ejectThreshold - 1
+		log.Printf("[WARN] backend %s ejected for %s", b.URL, p.ejectDuration)
+	}
+
+	for _, other := range p.backends {
+		if !now.Before(other.ejectedUntil) {
+			return false
+		}
+	}
+	return true
+}
+
+func withBackend(ctx context.Context, b *Backend) context.Context {
+	return context.WithValue(ctx, backendContextKey{}, b)
+}
+
+func backendFromContext(ctx context.Context) *Backend {
+	b, _ := ctx.Value(backendContextKey{}).(*Backend)
+	return b
+}
@@ -0,0 +1,121 @@
+package router
+
+import (
+	"testing"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/config"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestPool(t *testing.T, strategy string, ejectThreshold int) (*BackendPool, *fakeClock) {
+	t.Helper()
+	pool, err := NewBackendPool([]string{"http://a:8080", "http://b:8080", "http://c:8080"}, strategy, ejectThreshold, 30*time.Second)
+	if err != nil {
+		t.Fatalf("NewBackendPool: %v", err)
+	}
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	pool.now = clock.Now
+	return pool, clock
+}
+
+func acquireHosts(pool *BackendPool, n int, release bool) []string {
+	hosts := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		b := pool.Acquire()
+		hosts = append(hosts, b.URL.Host)
+		if release {
+			pool.Release(b)
+		}
+	}
+	return hosts
+}
+
+func assertHosts(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("hosts = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("hosts = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestBackendPool_RoundRobin(t *testing.T) {
+	pool, _ := newTestPool(t, config.LoadBalancingRoundRobin, 0)
+
+	assertHosts(t, acquireHosts(pool, 4, true), "a:8080", "b:8080", "c:8080", "a:8080")
+}
+
+func TestBackendPool_LeastConnections(t *testing.T) {
+	pool, _ := newTestPool(t, config.LoadBalancingLeastConnections, 0)
+
+	busy := acquireHosts(pool, 3, false)
+	assertHosts(t, busy, "a:8080", "b:8080", "c:8080")
+
+	// every backend has one request running; releasing b makes it the least busy
+	pool.Release(pool.backends[1])
+	assertHosts(t, acquireHosts(pool, 1, false), "b:8080")
+	pool.Release(pool.backends[0])
+	assertHosts(t, acquireHosts(pool, 1, false), "a:8080")
+}
+
+func TestBackendPool_PassiveEjection(t *testing.T) {
+	pool, clock := newTestPool(t, config.LoadBalancingRoundRobin, 2)
+	b := pool.backends[1]
+
+	if pool.MarkFailure(b) {
+		t.Fatal("pool reported as fully ejected")
+	}
+	assertHosts(t, acquireHosts(pool, 3, true), "a:8080", "b:8080", "c:8080")
+
+	pool.MarkFailure(b)
+	for _, host := range acquireHosts(pool, 4, true) {
+		if host == "b:8080" {
+			t.Fatal("ejected backend received a request")
+		}
+	}
+	if status := pool.Snapshot()[1]; !status.Ejected || status.EjectedUntil == nil {
+		t.Fatalf("snapshot of the ejected backend = %+v", status)
+	}
+
+	clock.now = clock.now.Add(30 * time.Second)
+	readmitted := false
+	for _, host := range acquireHosts(pool, 3, true) {
+		readmitted = readmitted || host == "b:8080"
+	}
+	if !readmitted {
+		t.Fatal("backend not re-admitted after its ejection")
+	}
+
+	// a single failure after re-admission ejects it again
+	pool.MarkFailure(b)
+	if !pool.Snapshot()[1].Ejected {
+		t.Fatal("re-admitted backend not ejected again on its first failure")
+	}
+
+	pool.MarkSuccess(b)
+	if status := pool.Snapshot()[1]; status.Ejected || status.Failures != 0 {
+		t.Fatalf("snapshot after a success = %+v", status)
+	}
+}
+
+func TestBackendPool_AllEjected(t *testing.T) {
+	pool, clock := newTestPool(t, config.LoadBalancingRoundRobin, 1)
+
+	pool.MarkFailure(pool.backends[0])
+	clock.now = clock.now.Add(time.Second)
+	pool.MarkFailure(pool.backends[2])
+	clock.now = clock.now.Add(time.Second)
+	if !pool.MarkFailure(pool.backends[1]) {
+		t.Fatal("pool not reported as fully ejected")
+	}
+
+	// the backend whose ejection ends first is still used
+	assertHosts(t, acquireHosts(pool, 2, true), "a:8080", "a:8080")
+}
@@ -3,24 +3,23 @@ package router
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"net/http/httputil"
-	"net/url"
 	"strings"
 	"time"
 
 	"github.com/raphael-guer1n/AREA/area-gateway/internal/core"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/health"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/metrics"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/middleware"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/registry"
 )
 
-func NewReverseProxy(baseURL string, stripPrefix string) http.Handler {
-	target, err := url.Parse(baseURL)
-	if err != nil {
-		panic("invalid service base URL: " + baseURL)
-	}
-
-	proxy := httputil.NewSingleHostReverseProxy(target)
+func NewReverseProxy(serviceName string, pool *BackendPool, stripPrefix string, breaker *health.Breaker) http.Handler {
+	proxy := &httputil.ReverseProxy{}
 
 	proxy.Transport = &http.Transport{
 		DialContext: (&net.Dialer{
@@ -28,7 +27,6 @@ func NewReverseProxy(baseURL string, stripPrefix string) http.Handler {
 			KeepAlive: 30 * time.Second,
 		}).DialContext,
 		TLSHandshakeTimeout:   3 * time.Second,
-		ResponseHeaderTimeout: 5 * time.Second,
 		ExpectContinueTimeout: 1 * time.Second,
 		IdleConnTimeout:       90 * time.Second,
 		MaxIdleConns:          100,
@@ -36,14 +34,24 @@ func NewReverseProxy(baseURL string, stripPrefix string) http.Handler {
 	}
 
 	proxy.Director = func(req *http.Request) {
-		clientIP := clientIPFromRemoteAddr(req.RemoteAddr)
+		target := backendFromContext(req.Context()).URL
+		clientIP := core.ClientIPFromRemoteAddr(req.RemoteAddr)
 
 		appendForwardedHeader(req, "X-Forwarded-For", clientIP)
 		appendForwardedHeader(req, "X-Forwarded-Host", req.Host)
 		appendForwardedHeader(req, "X-Forwarded-Proto", schemeFromRequest(req))
 
+		if rt := registry.RouteFromContext(req.Context()); rt == nil || !rt.Streaming {
+			// only streaming routes may switch protocols
+			req.Header.Del("Upgrade")
+			req.Header.Del("Connection")
+		}
+
 		req.Header.Set("X-Real-IP", clientIP)
 		req.Header.Del("X-Internal-Secret")
+		if requestID := middleware.GetRequestIDFromContext(req.Context()); requestID != "" {
+			req.Header.Set(middleware.RequestIDHeader, requestID)
+		}
 		req.URL.Scheme = target.Scheme
 		req.URL.Host = target.Host
 		req.Host = target.Host
@@ -69,8 +77,28 @@ func NewReverseProxy(baseURL string, stripPrefix string) http.Handler {
 		req.URL.Path = basePath + incomingPath
 	}
 
+	recordFailure := func(ctx context.Context, reason string) {
+		allEjected := pool.MarkFailure(backendFromContext(ctx))
+		if breaker != nil && allEjected {
+			breaker.Failure(reason)
+		}
+	}
+
 	proxy.ModifyResponse = func(res *http.Response) error {
-		removeHopByHopHeaders(res.Header)
+		switch res.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			recordFailure(res.Request.Context(), fmt.Sprintf("upstream returned status %d", res.StatusCode))
+		default:
+			pool.MarkSuccess(backendFromContext(res.Request.Context()))
+			if breaker != nil {
+				breaker.Success()
+			}
+		}
+		// the upgrade headers of a 101 are needed to hand the connection over;
+		// ReverseProxy strips the other hop-by-hop headers itself
+		if res.StatusCode != http.StatusSwitchingProtocols {
+			removeHopByHopHeaders(res.Header)
+		}
 		res.Header.Del("Server")
 		res.Header.Del("X-Powered-By")
 		return nil
@@ -79,9 +107,21 @@ func NewReverseProxy(baseURL string, stripPrefix string) http.Handler {
 	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
 		log.Printf("proxy error for %s %s: %v", r.Method, r.URL.Path, err)
 
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			// the client body went over the route limit, the upstream is fine
+			core.WriteError(w, http.StatusRequestEntityTooLarge, core.ErrPayloadTooLarge, "Request body too large")
+			return
+		}
+
+		if !errors.Is(err, context.Canceled) {
+			recordFailure(r.Context(), err.Error())
+		}
+
 		if errors.Is(err, context.DeadlineExceeded) {
-			core.WriteError(
+			writeUpstreamError(
 				w,
+				serviceName,
 				http.StatusGatewayTimeout,
 				core.ErrGatewayTimeout,
 				"Upstream service timeout",
@@ -92,16 +132,18 @@ func NewReverseProxy(baseURL string, stripPrefix string) http.Handler {
 		var netErr net.Error
 		if errors.As(err, &netErr) {
 			if netErr.Timeout() {
-				core.WriteError(
+				writeUpstreamError(
 					w,
+					serviceName,
 					http.StatusGatewayTimeout,
 					core.ErrGatewayTimeout,
 					"Upstream service timeout",
 				)
 				return
 			}
-			core.WriteError(
+			writeUpstreamError(
 				w,
+				serviceName,
 				http.StatusBadGateway,
 				core.ErrBadGateway,
 				"Upstream service unreachable",
@@ -109,30 +151,26 @@ func NewReverseProxy(baseURL string, stripPrefix string) http.Handler {
 			return
 		}
 
-		core.WriteError(
+		writeUpstreamError(
 			w,
+			serviceName,
 			http.StatusBadGateway,
 			core.ErrBadGateway,
 			"Upstream service unreachable",
 		)
 	}
 
-	return proxy
-}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		backend := pool.Acquire()
+		defer pool.Release(backend)
 
-func clientIPFromRemoteAddr(addr string) string {
-	if strings.HasPrefix(addr, "[") {
-		end := strings.Index(addr, "]")
-		if end != -1 {
-			return addr[1:end]
-		}
-	}
+		proxy.ServeHTTP(w, r.WithContext(withBackend(r.Context(), backend)))
+	})
+}
 
-	ip, _, err := net.SplitHostPort(addr)
-	if err == nil {
-		return ip
-	}
-	return addr
+func writeUpstreamError(w http.ResponseWriter, serviceName string, status int, code string, msg string) {
+	metrics.UpstreamErrorsTotal.Inc(serviceName, code)
+	core.WriteError(w, status, code, msg)
 }
 
 func appendForwardedHeader(req *http.Request, header string, value string) {
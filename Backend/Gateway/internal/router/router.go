@@ -1,11 +1,18 @@
 package router
 
 import (
+	"context"
+	"log"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/raphael-guer1n/AREA/area-gateway/internal/config"
 	"github.com/raphael-guer1n/AREA/area-gateway/internal/core"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/health"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/metrics"
 	"github.com/raphael-guer1n/AREA/area-gateway/internal/middleware"
+	"github.com/raphael-guer1n/AREA/area-gateway/internal/openapi"
 	"github.com/raphael-guer1n/AREA/area-gateway/internal/registry"
 )
 
@@ -16,7 +23,21 @@ type Router struct {
 	permMW     *middleware.PermissionsMiddleware
 	internalMW *middleware.InternalMiddleware
 	loggingMW  *middleware.LoggingMiddleware
+	rateMW     *middleware.RateLimitMiddleware
+	bodyMW     *middleware.BodyMiddleware
+	metricsMW  *middleware.MetricsMiddleware
+	identityMW *middleware.IdentityMiddleware
+	health     *health.Checker
+	docs       *openapi.Aggregator
 	mux        *http.ServeMux
+	proxies    map[string]proxyEntry
+	proxiesMu  sync.Mutex
+}
+
+type proxyEntry struct {
+	signature string
+	handler   http.Handler
+	pool      *BackendPool
 }
 
 func NewRouter(
@@ -26,6 +47,12 @@ func NewRouter(
 	perm *middleware.PermissionsMiddleware,
 	internal *middleware.InternalMiddleware,
 	logging *middleware.LoggingMiddleware,
+	rateLimit *middleware.RateLimitMiddleware,
+	body *middleware.BodyMiddleware,
+	metricsMW *middleware.MetricsMiddleware,
+	identity *middleware.IdentityMiddleware,
+	checker *health.Checker,
+	docs *openapi.Aggregator,
 ) *Router {
 	return &Router{
 		registry:   reg,
@@ -34,57 +61,166 @@ func NewRouter(
 		permMW:     perm,
 		internalMW: internal,
 		loggingMW:  logging,
+		rateMW:     rateLimit,
+		bodyMW:     body,
+		metricsMW:  metricsMW,
+		identityMW: identity,
+		health:     checker,
+		docs:       docs,
 		mux:        http.NewServeMux(),
+		proxies:    make(map[string]proxyEntry),
 	}
 }
 
-func (rt *Router) Build() (*http.ServeMux, error) {
+// proxyFor returns the cached proxy of the route's service, rebuilding it when
+// the service backends changed. Requests already holding the old proxy finish on it.
+func (rt *Router) proxyFor(route *registry.RegisteredRoute) http.Handler {
+	signature := poolSignature(route.Backends, route.LoadBalancing)
+
+	rt.proxiesMu.Lock()
+	defer rt.proxiesMu.Unlock()
+
+	entry, ok := rt.proxies[route.ServiceName]
+	if ok && entry.signature == signature {
+		return entry.handler
+	}
+
+	pool, err := NewBackendPool(
+		route.Backends,
+		route.LoadBalancing,
+		rt.config.UpstreamEjectThreshold,
+		time.Duration(rt.config.UpstreamEjectMs)*time.Millisecond,
+	)
+	if err != nil {
+		log.Printf("[ERROR] cannot build proxy for %s: %v", route.ServiceName, err)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeUpstreamError(w, route.ServiceName, http.StatusBadGateway, core.ErrBadGateway, "Upstream service misconfigured")
+		})
+	}
+
+	entry = proxyEntry{
+		signature: signature,
+		handler:   NewReverseProxy(route.ServiceName, pool, "/"+route.ServiceName, rt.health.Breaker(route.ServiceName)),
+		pool:      pool,
+	}
+	rt.proxies[route.ServiceName] = entry
+	return entry.handler
+}
 
-	routes := rt.registry.ListAllRoutes()
+// SyncProxies aligns the proxy cache with the registry after a config reload.
+func (rt *Router) SyncProxies() {
+	services := make(map[string]string)
+	for _, svc := range rt.registry.ListServices() {
+		services[svc.Name] = poolSignature(svc.Backends(), svc.LoadBalancing)
+	}
 
-	proxies := make(map[string]http.Handler)
-	for _, route := range routes {
-		if _, exists := proxies[route.ServiceName]; exists {
-			continue
+	rt.proxiesMu.Lock()
+	for name, entry := range rt.proxies {
+		if signature, ok := services[name]; !ok || signature != entry.signature {
+			delete(rt.proxies, name)
 		}
-		proxies[route.ServiceName] = NewReverseProxy(route.BaseURL, "/"+route.ServiceName)
 	}
+	rt.proxiesMu.Unlock()
+
+	for _, route := range rt.registry.ListAllRoutes() {
+		rt.proxyFor(&route)
+	}
+}
+
+func (rt *Router) Build() (*http.ServeMux, error) {
+
+	rt.SyncProxies()
+
+	rt.mux.Handle("/gateway/health", rt.health.Handler())
+	rt.mux.Handle("/gateway/openapi.json", rt.docs.Handler())
+	rt.mux.Handle("/metrics", rt.internalMW.Guard(metrics.Handler()))
+	rt.mux.Handle("/gateway/admin/services", rt.internalMW.Guard(http.HandlerFunc(rt.adminServices)))
+	rt.mux.Handle("/gateway/admin/routes", rt.internalMW.Guard(http.HandlerFunc(rt.adminRoutes)))
+	rt.mux.Handle("/gateway/admin/resolve", rt.internalMW.Guard(http.HandlerFunc(rt.adminResolve)))
 
 	rt.mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		route, err := rt.registry.FindRoute(r.URL.Path, r.Method)
 		if err != nil {
-			if _, pathErr := rt.registry.FindRouteByPath(r.URL.Path); pathErr == nil {
-				core.WriteError(
-					w,
-					http.StatusMethodNotAllowed,
-					core.ErrForbidden,
-					"Method not allowed",
-				)
-				return
-			}
-
-			core.WriteError(
-				w,
-				http.StatusNotFound,
-				core.ErrNotFound,
-				"Route not found",
-			)
+			rt.metricsMW.Handler(http.HandlerFunc(rt.notFound)).ServeHTTP(w, r)
 			return
 		}
+		r = r.WithContext(registry.WithRoute(r.Context(), route))
 
-		proxy, ok := proxies[route.ServiceName]
-		if !ok {
-			proxy = NewReverseProxy(route.BaseURL, "/"+route.ServiceName)
-			proxies[route.ServiceName] = proxy
-		}
+		proxy := rt.proxyFor(route)
 
-		handler := rt.permMW.Handler(proxy)
+		handler := rt.withTimeout(route, proxy)
+		handler = rt.circuitGuard(route.ServiceName, handler)
+		handler = rt.identityMW.Handler(handler)
+		handler = rt.permMW.Handler(handler)
+		handler = rt.rateMW.Handler(handler)
 		handler = rt.authMW.Handler(handler)
+		handler = rt.bodyMW.Handler(handler)
 		handler = rt.internalMW.Handler(handler)
 		handler = rt.loggingMW.Handler(handler)
+		handler = rt.metricsMW.Handler(handler)
 
 		handler.ServeHTTP(w, r)
 	}))
 
 	return rt.mux, nil
 }
+
+func (rt *Router) notFound(w http.ResponseWriter, r *http.Request) {
+	if _, pathErr := rt.registry.FindRouteByPath(r.URL.Path); pathErr == nil {
+		core.WriteError(
+			w,
+			http.StatusMethodNotAllowed,
+			core.ErrForbidden,
+			"Method not allowed",
+		)
+		return
+	}
+
+	core.WriteError(
+		w,
+		http.StatusNotFound,
+		core.ErrNotFound,
+		"Route not found",
+	)
+}
+
+func (rt *Router) circuitGuard(service string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !rt.health.Breaker(service).Allow() {
+			writeUpstreamError(
+				w,
+				service,
+				http.StatusServiceUnavailable,
+				core.ErrCircuitOpen,
+				"Upstream service unavailable",
+			)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withTimeout bounds the upstream call with the route timeout, or the gateway
+// default. The deadline is carried by the request context, so the upstream
+// connection is torn down as soon as it expires.
+func (rt *Router) withTimeout(route *registry.RegisteredRoute, next http.Handler) http.Handler {
+	if route.Streaming {
+		// streams last until the client or the upstream closes them
+		return next
+	}
+
+	timeoutMs := route.TimeoutMs
+	if timeoutMs <= 0 {
+		timeoutMs = rt.config.RequestTimeoutMs
+	}
+	if timeoutMs <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(timeoutMs)*time.Millisecond)
+		defer cancel()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
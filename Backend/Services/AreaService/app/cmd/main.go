@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/raphael-guer1n/AREA/AreaService/internal/config"
 	"github.com/raphael-guer1n/AREA/AreaService/internal/db"
@@ -16,9 +18,28 @@ func main() {
 	dbConn := db.Connect(cfg)
 
 	areaRepository := repository.NewAreaRepository(dbConn)
+	executionRepository := repository.NewExecutionRepository(dbConn)
+	reactionJobRepository := repository.NewReactionJobRepository(dbConn)
+	delayedTriggerRepository := repository.NewDelayedTriggerRepository(dbConn)
 
 	areaSvc := service.NewAreaService(areaRepository, cfg.InternalSecret)
-	areaHandler := httphandler.NewAreaHandler(areaSvc, cfg)
+	executionSvc := service.NewExecutionService(executionRepository, time.Duration(cfg.ExecutionRetention)*24*time.Hour)
+	go executionSvc.StartCleanup(context.Background(), time.Hour)
+
+	reactionQueue := service.NewReactionQueue(
+		reactionJobRepository,
+		executionSvc,
+		cfg.ReactionWorkers,
+		cfg.ReactionMaxAttempts,
+		time.Duration(cfg.ReactionRetryBaseMs)*time.Millisecond,
+		time.Duration(cfg.ReactionRetryMaxMs)*time.Millisecond,
+	)
+
+	scheduler := service.NewDelayedScheduler(delayedTriggerRepository)
+
+	areaHandler := httphandler.NewAreaHandler(areaSvc, executionSvc, reactionQueue, scheduler, cfg)
+	reactionQueue.Start(context.Background(), areaHandler.RunReactionJob)
+	scheduler.Start(context.Background(), areaHandler.RunDelayedTrigger)
 	router := httphandler.NewRouter(areaHandler)
 
 	addr := ":" + cfg.HTTPPort
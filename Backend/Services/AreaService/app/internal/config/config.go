@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -20,6 +21,11 @@ type Config struct {
 	DelActionsUrls        map[string]string
 	ActivateActionsUrls   map[string]string
 	DeactivateActionsUrls map[string]string
+	ExecutionRetention    int // days
+	ReactionWorkers       int
+	ReactionMaxAttempts   int
+	ReactionRetryBaseMs   int
+	ReactionRetryMaxMs    int
 }
 
 func Load() Config {
@@ -43,6 +49,11 @@ func Load() Config {
 		DelActionsUrls:        delActionsUrls,
 		ActivateActionsUrls:   activateActionsUrls,
 		DeactivateActionsUrls: deactivateActionsUrls,
+		ExecutionRetention:    getIntEnv("EXECUTION_RETENTION_DAYS", 30),
+		ReactionWorkers:       getIntEnv("REACTION_WORKERS", 4),
+		ReactionMaxAttempts:   getIntEnv("REACTION_MAX_ATTEMPTS", 5),
+		ReactionRetryBaseMs:   getIntEnv("REACTION_RETRY_BASE_MS", 2000),
+		ReactionRetryMaxMs:    getIntEnv("REACTION_RETRY_MAX_MS", 300000),
 	}
 }
 
@@ -75,6 +86,14 @@ func GetActionsUrls(envVarName string) map[string]string {
 	return urls
 }
 
+func getIntEnv(key string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
 func getEnv(key, def string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
@@ -16,11 +16,25 @@ type AreaAction struct {
 }
 
 type AreaReaction struct {
-	ID       int          `json:"id"`
-	Provider string       `json:"provider"`
-	Service  string       `json:"service"`
-	Title    string       `json:"title"`
-	Input    []InputField `json:"input"`
+	ID        int                `json:"id"`
+	Provider  string             `json:"provider"`
+	Service   string             `json:"service"`
+	Title     string             `json:"title"`
+	Input     []InputField       `json:"input"`
+	Condition *ReactionCondition `json:"condition,omitempty"`
+}
+
+// ReactionCondition restricts the triggers a reaction runs on. A rule compares
+// an output field of the action with a value; a group holds Conditions joined
+// by Mode, "all" (AND, the default) or "any" (OR).
+type ReactionCondition struct {
+	Field           string              `json:"field,omitempty"`
+	Operator        string              `json:"operator,omitempty"`
+	Value           any                 `json:"value,omitempty"`
+	Values          []any               `json:"values,omitempty"`
+	CaseInsensitive bool                `json:"case_insensitive,omitempty"`
+	Mode            string              `json:"mode,omitempty"`
+	Conditions      []ReactionCondition `json:"conditions,omitempty"`
 }
 
 type Area struct {
@@ -32,15 +46,29 @@ type Area struct {
 	Reactions []AreaReaction `json:"reactions"`
 }
 
+// AreaDiff lists the rows to touch when an area is edited. Unchanged rows are
+// left out, so only the created and updated actions have to be registered
+// again with their trigger service.
+type AreaDiff struct {
+	CreatedActions   []AreaAction
+	UpdatedActions   []AreaAction
+	DeletedActions   []AreaAction
+	CreatedReactions []AreaReaction
+	UpdatedReactions []AreaReaction
+	DeletedReactions []AreaReaction
+}
+
 type AreaRepository interface {
 	GetUserAreas(userID int) ([]Area, error)
 	GetAreaActions(areaID int) ([]AreaAction, error)
 	GetAreaReactions(areaID int) ([]AreaReaction, error)
 	SaveArea(area Area) (Area, error)
+	SaveAreas(areas []Area) ([]Area, error)
 	SaveActions(areaID int, actions []AreaAction) ([]AreaAction, error)
 	SaveReactions(areaID int, reactions []AreaReaction) ([]AreaReaction, error)
 	GetAreaFromAction(actionId int) (Area, error)
 	GetArea(areaID int) (Area, error)
+	UpdateArea(area Area, diff AreaDiff) (Area, error)
 	ToggleArea(areaID int, isActive bool) error
 	DeleteArea(areaID int) error
 	DeactivateAreasByProvider(userID int, provider string) (int, error)
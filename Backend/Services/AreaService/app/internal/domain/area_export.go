@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+const (
+	AreaExportFormat  = "area-export"
+	AreaExportVersion = 1
+)
+
+// AreaExport is the versioned document areas are exported to and imported
+// from. It holds no ids, user or provider token, and the inputs of secret
+// fields are exported empty.
+type AreaExport struct {
+	Format     string         `json:"format"`
+	Version    int            `json:"version"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Areas      []ExportedArea `json:"areas"`
+}
+
+type ExportedArea struct {
+	Name      string             `json:"name"`
+	Active    bool               `json:"active"`
+	Actions   []ExportedAction   `json:"actions"`
+	Reactions []ExportedReaction `json:"reactions"`
+	// Secrets lists the inputs exported empty, as actions.<n>.<name> or
+	// reactions.<n>.<name>. They have to be filled in before an import.
+	Secrets []string `json:"secrets,omitempty"`
+}
+
+type ExportedAction struct {
+	Provider string       `json:"provider"`
+	Service  string       `json:"service"`
+	Title    string       `json:"title"`
+	Type     string       `json:"type"`
+	Input    []InputField `json:"input"`
+}
+
+type ExportedReaction struct {
+	Provider  string             `json:"provider"`
+	Service   string             `json:"service"`
+	Title     string             `json:"title"`
+	Input     []InputField       `json:"input"`
+	Condition *ReactionCondition `json:"condition,omitempty"`
+}
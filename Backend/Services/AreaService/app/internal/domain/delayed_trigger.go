@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	DelayedCronAction    = "cron_action"
+	DelayedCalendarEvent = "calendar_event"
+)
+
+// DelayedTrigger is a trigger scheduled for later. It is stored so that it
+// survives restarts, deleted once it ran, and kept as failed otherwise. Kind
+// tells how to read Payload: the output fields of the cron action ActionID, or
+// a CalendarEventPayload.
+type DelayedTrigger struct {
+	ID        int             `json:"id"`
+	Kind      string          `json:"kind"`
+	ActionID  int             `json:"action_id,omitempty"`
+	Payload   json.RawMessage `json:"payload"`
+	DueAt     time.Time       `json:"due_at"`
+	Status    string          `json:"status"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// CalendarEventPayload is a delayed calendar event. The user token is fetched
+// when the event is due rather than stored.
+type CalendarEventPayload struct {
+	UserID int   `json:"user_id"`
+	Event  Event `json:"event"`
+}
+
+type DelayedTriggerRepository interface {
+	// Schedule stores a trigger due after delay. A pending trigger of the
+	// same cron action is replaced.
+	Schedule(trigger DelayedTrigger, delay time.Duration) (DelayedTrigger, error)
+	// Claim locks the next due trigger and counts the attempt. Triggers locked
+	// for longer than staleAfter are claimed again, their replica being
+	// presumed dead.
+	Claim(staleAfter time.Duration) (*DelayedTrigger, error)
+	Complete(id int) error
+	Fail(id int, lastError string) error
+}
@@ -0,0 +1,23 @@
+package domain
+
+// ReactionRequest is a reaction request as rendered for a test of an area,
+// with its secrets masked.
+type ReactionRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+}
+
+// ReactionDryRun is the outcome of one reaction of an area test. Result is
+// only set when the test was live and the request was sent.
+type ReactionDryRun struct {
+	ReactionID int                `json:"reaction_id,omitempty"`
+	Position   int                `json:"position"`
+	Service    string             `json:"service"`
+	Title      string             `json:"title"`
+	Skipped    bool               `json:"skipped,omitempty"`
+	Request    *ReactionRequest   `json:"request,omitempty"`
+	Result     *ReactionExecution `json:"result,omitempty"`
+	Error      string             `json:"error,omitempty"`
+}
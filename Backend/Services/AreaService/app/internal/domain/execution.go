@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+const (
+	ExecutionQueued  = "queued"
+	ExecutionSuccess = "success"
+	ExecutionFailed  = "failed"
+	ExecutionSkipped = "skipped"
+)
+
+// ReactionExecution is the outcome of one reaction of a trigger. The request
+// is recorded as rendered, with the values taken from the environment masked.
+// Reactions whose condition did not match are recorded as skipped.
+type ReactionExecution struct {
+	ID              int    `json:"id"`
+	ReactionID      int    `json:"reaction_id"`
+	Attempt         int    `json:"attempt"`
+	Skipped         bool   `json:"skipped,omitempty"`
+	Method          string `json:"method,omitempty"`
+	URL             string `json:"url,omitempty"`
+	RequestBody     string `json:"request_body,omitempty"`
+	StatusCode      int    `json:"status_code,omitempty"`
+	ResponseSnippet string `json:"response_snippet,omitempty"`
+	DurationMs      int64  `json:"duration_ms"`
+	Error           string `json:"error,omitempty"`
+	// Outputs are the fields mapped from the response, offered to the next
+	// reactions of the area.
+	Outputs []InputField `json:"outputs,omitempty"`
+}
+
+// Execution records a trigger of an area: the action that fired, the fields
+// it sent and the result of every reaction attempt. It stays queued until all
+// of its reaction jobs are done.
+type Execution struct {
+	ID           int                 `json:"id"`
+	AreaID       int                 `json:"area_id"`
+	ActionID     int                 `json:"action_id"`
+	RequestID    string              `json:"request_id"`
+	OutputFields []InputField        `json:"output_fields"`
+	Status       string              `json:"status"`
+	Error        string              `json:"error,omitempty"`
+	StartedAt    time.Time           `json:"started_at"`
+	DurationMs   int64               `json:"duration_ms"`
+	Reactions    []ReactionExecution `json:"reactions"`
+}
+
+type ExecutionRepository interface {
+	Create(execution Execution) (Execution, error)
+	AddReaction(executionID int, reaction ReactionExecution) error
+	// ReactionOutputs returns the outputs of the succeeded reactions of the
+	// execution, by reaction id.
+	ReactionOutputs(executionID int) (map[int][]InputField, error)
+	ListByArea(areaID int, limit int, offset int) ([]Execution, int, error)
+	DeleteOlderThan(before time.Time) (int64, error)
+}
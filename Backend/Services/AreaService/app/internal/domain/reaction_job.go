@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+// ReactionJob is a queued run of one reaction of a trigger. Jobs are deleted
+// once the reaction succeeds, or moved to the dead letters once it has
+// failed MaxAttempts times. A chained job uses the outputs of earlier
+// reactions and waits for the jobs and dead letters of lower Position of its
+// execution.
+type ReactionJob struct {
+	ID           int          `json:"id"`
+	ExecutionID  int          `json:"execution_id"`
+	AreaID       int          `json:"area_id"`
+	ReactionID   int          `json:"reaction_id"`
+	Position     int          `json:"position"`
+	Chained      bool         `json:"chained"`
+	UserID       int          `json:"user_id"`
+	RequestID    string       `json:"request_id"`
+	OutputFields []InputField `json:"output_fields"`
+	Attempts     int          `json:"attempts"`
+	MaxAttempts  int          `json:"max_attempts"`
+	NextRunAt    time.Time    `json:"next_run_at"`
+	LastError    string       `json:"last_error,omitempty"`
+}
+
+// DeadLetter is a reaction job that exhausted its attempts or failed for good,
+// or a chained job whose upstream reaction did. Replaying it queues the job
+// again with a fresh attempt budget, at the same Position.
+type DeadLetter struct {
+	ID             int          `json:"id"`
+	ExecutionID    int          `json:"execution_id"`
+	AreaID         int          `json:"area_id"`
+	ReactionID     int          `json:"reaction_id"`
+	Position       int          `json:"position"`
+	Chained        bool         `json:"chained"`
+	UserID         int          `json:"user_id"`
+	RequestID      string       `json:"request_id"`
+	OutputFields   []InputField `json:"output_fields"`
+	Attempts       int          `json:"attempts"`
+	LastError      string       `json:"last_error"`
+	LastStatusCode int          `json:"last_status_code,omitempty"`
+	FailedAt       time.Time    `json:"failed_at"`
+}
+
+type ReactionJobRepository interface {
+	// Enqueue stores the execution, the reactions it already settled and its
+	// jobs together.
+	Enqueue(execution Execution, jobs []ReactionJob) (Execution, error)
+	// Claim locks the next due job and counts the attempt. Jobs locked for
+	// longer than staleAfter are claimed again, their worker being presumed dead.
+	Claim(staleAfter time.Duration) (*ReactionJob, error)
+	// Complete, Bury and Replay also settle the status of the execution.
+	Complete(job ReactionJob) error
+	Retry(job ReactionJob, delay time.Duration, lastError string) error
+	// Bury moves the job to the dead letters, along with the later chained
+	// jobs of its execution, which would miss its outputs.
+	Bury(job ReactionJob, lastError string, lastStatusCode int) error
+	ListDeadLetters(userID int, areaID int, limit int, offset int) ([]DeadLetter, int, error)
+	GetDeadLetter(id int) (*DeadLetter, error)
+	Replay(letter DeadLetter, maxAttempts int) (ReactionJob, error)
+}
@@ -6,6 +6,8 @@ type FieldConfig struct {
 	Label         string `json:"label"`
 	Required      bool   `json:"required"`
 	DefaultValuer string `json:"default"`
+	// Secret fields hold credentials, left out when areas are exported.
+	Secret bool `json:"secret,omitempty"`
 }
 
 type OutputFieldConfig struct {
@@ -14,6 +16,14 @@ type OutputFieldConfig struct {
 	Label string `json:"label"`
 }
 
+// ReactionOutputMapping extracts a field from the JSON response of a reaction
+// for the next reactions of the area.
+type ReactionOutputMapping struct {
+	FieldKey string `json:"field_key"`
+	JSONPath string `json:"json_path"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
 type ActionConfig struct {
 	Title        string              `json:"title"`
 	Label        string              `json:"label"`
@@ -31,6 +41,7 @@ type ReactionConfig struct {
 	BodyType   string        `json:"bodyType"`
 	BodyStruct []BodyField   `json:"body_struct"`
 	Headers    map[string]string `json:"headers,omitempty"`
+	OutputMappings []ReactionOutputMapping `json:"output_mappings,omitempty"`
 }
 
 type ServiceConfig struct {
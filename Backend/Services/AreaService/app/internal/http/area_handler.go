@@ -15,17 +15,28 @@ import (
 	"github.com/raphael-guer1n/AREA/AreaService/internal/config"
 	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
 	"github.com/raphael-guer1n/AREA/AreaService/internal/service"
+	"github.com/raphael-guer1n/AREA/Shared/identity"
 )
 
+// triggerClient bounds the calls a delayed trigger makes, so a hanging
+// endpoint cannot hold the scheduler.
+var triggerClient = &http.Client{Timeout: 30 * time.Second}
+
 type AreaHandler struct {
-	areaService *service.AreaService
-	cfg         config.Config
+	areaService      *service.AreaService
+	executionService *service.ExecutionService
+	reactionQueue    *service.ReactionQueue
+	scheduler        *service.DelayedScheduler
+	cfg              config.Config
 }
 
-func NewAreaHandler(authSvc *service.AreaService, cfg config.Config) *AreaHandler {
+func NewAreaHandler(authSvc *service.AreaService, executionSvc *service.ExecutionService, reactionQueue *service.ReactionQueue, scheduler *service.DelayedScheduler, cfg config.Config) *AreaHandler {
 	return &AreaHandler{
-		areaService: authSvc,
-		cfg:         cfg,
+		areaService:      authSvc,
+		executionService: executionSvc,
+		reactionQueue:    reactionQueue,
+		scheduler:        scheduler,
+		cfg:              cfg,
 	}
 }
 
@@ -66,18 +77,21 @@ func (h *AreaHandler) HandleCreateEventArea(w http.ResponseWriter, req *http.Req
 	}
 
 	if body.Delay > 0 {
-		time.AfterFunc(time.Duration(body.Delay)*time.Second, func() {
-			event, err := h.areaService.CreateCalendarEvent(token, body.Event)
-			if err != nil {
-				log.Printf("Error creating delayed calendar event: %v", err)
-				return
-			}
-			log.Printf("Delayed calendar event created successfully: %s", event.Summary)
-		})
-
+		trigger, err := h.scheduler.ScheduleCalendarEvent(userId, body.Event, time.Duration(body.Delay)*time.Second)
+		if err != nil {
+			respondJSON(w, http.StatusInternalServerError, map[string]any{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
 		respondJSON(w, http.StatusAccepted, map[string]any{
 			"success": true,
 			"message": fmt.Sprintf("Event will be created in %d seconds", body.Delay),
+			"data": map[string]any{
+				"delayed_trigger_id": trigger.ID,
+				"due_at":             trigger.DueAt,
+			},
 		})
 		return
 	}
@@ -269,6 +283,14 @@ func (h *AreaHandler) getReactionDetails(reaction domain.AreaReaction) (domain.R
 }
 
 func (h *AreaHandler) getUserId(r *http.Request) (int, error) {
+	id, err := identity.FromRequest(r, h.cfg.InternalSecret)
+	if err == nil {
+		return id.UserID, nil
+	}
+	if !errors.Is(err, identity.ErrNoIdentity) {
+		return 0, err
+	}
+
 	endpoint := strings.TrimRight(h.cfg.AuthServiceURL, "/") + "/auth/me"
 	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
 	if err != nil {
@@ -764,239 +786,192 @@ func (h *AreaHandler) HandleActionTrigger(w http.ResponseWriter, req *http.Reque
 		})
 		return
 	}
+	requestID := service.RequestIDOrNew(req.Header.Get(service.RequestIDHeader))
+	execution := domain.Execution{
+		AreaID:       area.ID,
+		ActionID:     body.ActionId,
+		RequestID:    requestID,
+		OutputFields: body.OutputFields,
+		StartedAt:    time.Now(),
+		Reactions:    make([]domain.ReactionExecution, 0, len(area.Reactions)),
+	}
 	if area.Active != true {
+		execution.Status = domain.ExecutionSkipped
+		execution.Error = "area inactive"
+		h.recordExecution(execution)
 		respondJSON(w, http.StatusOK, map[string]any{
 			"success": false,
 			"error":   "Inactive area, you can't trigger actions on it",
 		})
 		return
 	}
-	for _, reaction := range area.Reactions {
-		err := h.TriggerReaction(reaction, body.OutputFields, userId)
-		if err != nil {
-			respondJSON(w, http.StatusInternalServerError, map[string]any{
-				"success": false,
-				"error":   err.Error(),
-			})
-			return
-		}
+	execution, err = h.reactionQueue.Enqueue(execution, area.Reactions, userId)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
 	}
-	respondJSON(w, http.StatusOK, map[string]any{})
-	return
+	log.Printf("Queued %d reactions of area %d from action %d (request_id=%s)", len(area.Reactions), area.ID, body.ActionId, requestID)
+	respondJSON(w, http.StatusAccepted, map[string]any{
+		"success": true,
+		"data": map[string]any{
+			"execution_id": execution.ID,
+			"queued":       len(area.Reactions),
+		},
+	})
 }
 
-func checkFieldsValidity(fields []domain.InputField, config []domain.FieldConfig) error {
-	for _, field := range config {
-		if field.Required {
-			var found bool
-			for _, inputField := range fields {
-				if inputField.Name == field.Name && inputField.Value != "" {
-					found = true
-					break
-				}
-			}
-			if !found {
-				return fmt.Errorf("field %s is required", field.Name)
-			}
-		}
+// RunReactionJob runs the reaction of a queued job against the current state
+// of its area.
+func (h *AreaHandler) RunReactionJob(job domain.ReactionJob) (domain.ReactionExecution, error) {
+	failed := domain.ReactionExecution{ReactionID: job.ReactionID}
+	area, err := h.areaService.GetArea(job.AreaID)
+	if err != nil {
+		failed.Error = err.Error()
+		return failed, fmt.Errorf("%w: loading area %d: %v", service.ErrRetryable, job.AreaID, err)
 	}
-	return nil
-}
-
-func CheckAreaValidity(area domain.Area, config domain.AreaConfig) error {
-	for i, action := range area.Actions {
-		configAction := config.Actions[i]
-		err := checkFieldsValidity(action.Input, configAction.Fields)
-		if err != nil {
-			return err
+	for _, reaction := range area.Reactions {
+		if reaction.ID != job.ReactionID {
+			continue
 		}
-	}
-	for i, reaction := range area.Reactions {
-		configReaction := config.Reactions[i]
-		err := checkFieldsValidity(reaction.Input, configReaction.Fields)
+		chained, err := h.executionService.ChainedFields(job.ExecutionID, area.Reactions, reaction)
 		if err != nil {
-			return err
+			failed.Error = err.Error()
+			return failed, err
 		}
+		return h.TriggerReaction(job.RequestID, reaction, append(job.OutputFields, chained...), job.UserID)
 	}
-	return nil
+	err = fmt.Errorf("reaction %d is no longer part of area %d", job.ReactionID, job.AreaID)
+	failed.Error = err.Error()
+	return failed, err
 }
 
-func (h *AreaHandler) TriggerReaction(areaReaction domain.AreaReaction, outputFields []domain.InputField, userId int) error {
-	serviceProfile := domain.UserService{}
-	var err error
-	if strings.TrimSpace(areaReaction.Provider) != "" {
-		serviceProfile, err = h.getUserServiceProfile(userId, areaReaction.Provider)
-		if err != nil {
-			return err
-		}
-	}
-	reactionConfig, err := h.getReactionDetails(areaReaction)
-	if err != nil {
-		return err
+// recordExecution stores the trigger in the area history. A failure is only
+// logged: the history must not change the outcome of the trigger.
+func (h *AreaHandler) recordExecution(execution domain.Execution) {
+	execution.DurationMs = time.Since(execution.StartedAt).Milliseconds()
+	if _, err := h.executionService.Record(execution); err != nil {
+		log.Printf("Failed to record execution of area %d (request_id=%s): %v", execution.AreaID, execution.RequestID, err)
 	}
+}
 
-	fieldValues := make(map[string]string)
-	for _, field := range areaReaction.Input {
-		for _, outputField := range outputFields {
-			field.Value = strings.ReplaceAll(field.Value, "{{"+outputField.Name+"}}", outputField.Value)
-		}
-		if strings.TrimSpace(areaReaction.Provider) != "" {
-			for _, serviceField := range serviceProfile.Fields {
-				field.Value = strings.ReplaceAll(field.Value, "{{"+serviceField.FieldKey+"}}", serviceField.StringValue)
-			}
-		}
-		fieldValues[field.Name] = field.Value
+func (h *AreaHandler) HandleGetAreaExecutions(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		respondJSON(w, http.StatusMethodNotAllowed, map[string]any{
+			"success": false,
+			"error":   "method not allowed",
+		})
+		return
 	}
-	if strings.TrimSpace(areaReaction.Provider) != "" {
-		for _, serviceField := range serviceProfile.Fields {
-			fieldValues[serviceField.FieldKey] = serviceField.StringValue
-		}
+	query := req.URL.Query()
+	areaId, err := strconv.Atoi(query.Get("area_id"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]any{
+			"success": false,
+			"error":   "area_id is required",
+		})
+		return
 	}
-	userToken := ""
-	if strings.TrimSpace(areaReaction.Provider) != "" {
-		userToken = serviceProfile.Profile.AccessToken
+	page, pageSize, err := parsePage(query)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
 	}
-	return h.areaService.LaunchReactions(userToken, fieldValues, reactionConfig)
-}
-
-type actionRequest struct {
-	Active   bool                `json:"active"`
-	ActionID int                 `json:"action_id"`
-	Type     string              `json:"type"`
-	Provider string              `json:"provider"`
-	Service  string              `json:"service"`
-	Title    string              `json:"title"`
-	Input    []domain.InputField `json:"input"`
-}
 
-func (h *AreaHandler) TriggerAction(areaAction []domain.AreaAction, isActive bool, authHeader string) error {
-	cronActions := make([]domain.AreaAction, 0)
-	otherActions := make([]domain.AreaAction, 0)
-	for _, action := range areaAction {
-		action.Active = isActive
-		if action.Type == "cron" {
-			cronActions = append(cronActions, action)
-		} else {
-			otherActions = append(otherActions, action)
-		}
+	userId, err := h.getUserId(req)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   "Error getting user ID," + err.Error(),
+		})
+		return
 	}
-	if isActive {
-		for _, action := range cronActions {
-			err := h.TriggerCronAction(action)
-			if err != nil {
-				return err
-			}
-		}
+	area, err := h.areaService.GetArea(areaId)
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]any{
+			"success": false,
+			"error":   "area not found",
+		})
+		return
 	}
-
-	actionsByType := make(map[string][]actionRequest)
-	for _, action := range otherActions {
-		action.Active = isActive
-		actionsByType[action.Type] = append(actionsByType[action.Type], actionRequest{
-			Active:   action.Active,
-			ActionID: action.ID,
-			Type:     action.Type,
-			Provider: action.Provider,
-			Service:  action.Service,
-			Title:    action.Title,
-			Input:    action.Input,
+	if area.UserID != userId {
+		respondJSON(w, http.StatusForbidden, map[string]any{
+			"success": false,
+			"error":   "You are not allowed to see the history of this area",
 		})
+		return
 	}
 
-	for actionType, actions := range actionsByType {
-		createUrl, exist := h.cfg.CreateActionsUrls[actionType]
-		if !exist {
-			return fmt.Errorf("action type %s not supported", actionType)
-		}
-		var body struct {
-			Actions []actionRequest `json:"actions"`
-		}
-		body.Actions = actions
-		payload, err := json.Marshal(body)
-		if err != nil {
-			return err
-		}
-		resp, err := http.NewRequest(http.MethodPost, createUrl, bytes.NewBuffer(payload))
-		if err != nil {
-			return err
-		}
-		if strings.TrimSpace(authHeader) != "" {
-			resp.Header.Set("Authorization", authHeader)
-		} else if h.cfg.InternalSecret != "" {
-			resp.Header.Set("Authorization", "Bearer "+h.cfg.InternalSecret)
-		}
-		if h.cfg.InternalSecret != "" {
-			resp.Header.Set("X-Internal-Secret", h.cfg.InternalSecret)
-		}
-		client := &http.Client{}
-		_, err = client.Do(resp)
-		if err != nil {
-			return err
-		}
+	executions, total, err := h.executionService.ListByArea(areaId, page, pageSize)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
 	}
-	return nil
+	respondJSON(w, http.StatusOK, map[string]any{
+		"success": true,
+		"data": map[string]any{
+			"executions": executions,
+			"page":       page,
+			"page_size":  pageSize,
+			"total":      total,
+		},
+	})
 }
 
-func (h *AreaHandler) TriggerCronAction(areaAction domain.AreaAction) error {
-	if areaAction.Type != "cron" {
-		return fmt.Errorf("only cron actions are supported")
-	}
-	var delay int
-	outputFields := make([]domain.InputField, 0, len(areaAction.Input))
-	for _, input := range areaAction.Input {
-		if input.Name == "delay" {
-			delay, _ = strconv.Atoi(input.Value)
+// parsePage reads the optional page and page_size query parameters.
+func parsePage(query url.Values) (int, int, error) {
+	page, pageSize := 1, service.DefaultExecutionPageSize
+	var err error
+	if raw := query.Get("page"); raw != "" {
+		if page, err = strconv.Atoi(raw); err != nil || page < 1 {
+			return 0, 0, errors.New("page must be a positive integer")
 		}
-		outputFields = append(outputFields, domain.InputField{Name: input.Name, Value: input.Value})
 	}
-	log.Printf("Triggering action %d in %d seconds", areaAction.ID, delay)
-	time.AfterFunc(time.Duration(delay)*time.Second, func() {
-		var body struct {
-			OutputFields []domain.InputField `json:"output_fields"`
-			ActionId     int                 `json:"action_id"`
-		}
-		body.OutputFields = outputFields
-		body.ActionId = areaAction.ID
-		payload, err := json.Marshal(body)
-		if err != nil {
-			log.Fatal(err)
-			return
-		}
-		endpoint := strings.TrimRight(h.cfg.AreaServiceURL, "/") + "/triggerArea"
-		req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(payload))
-		if err != nil {
-			log.Fatal(err)
-		}
-		if h.cfg.InternalSecret != "" {
-			req.Header.Set("X-Internal-Secret", h.cfg.InternalSecret)
-		}
-		_, err = http.DefaultClient.Do(req)
-		if err != nil {
-			log.Fatal(err)
-			return
+	if raw := query.Get("page_size"); raw != "" {
+		if pageSize, err = strconv.Atoi(raw); err != nil || pageSize < 1 || pageSize > service.MaxExecutionPageSize {
+			return 0, 0, fmt.Errorf("page_size must be between 1 and %d", service.MaxExecutionPageSize)
 		}
-	})
-	return nil
+	}
+	return page, pageSize, nil
 }
 
-func (h *AreaHandler) HandleDeleteArea(w http.ResponseWriter, req *http.Request) {
-	if req.Method != http.MethodPost {
+func (h *AreaHandler) HandleGetDeadLetters(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
 		respondJSON(w, http.StatusMethodNotAllowed, map[string]any{
 			"success": false,
 			"error":   "method not allowed",
 		})
 		return
 	}
-	var body struct {
-		AreaId int `json:"area_id"`
+	query := req.URL.Query()
+	areaId := 0
+	if raw := query.Get("area_id"); raw != "" {
+		var err error
+		if areaId, err = strconv.Atoi(raw); err != nil || areaId < 1 {
+			respondJSON(w, http.StatusBadRequest, map[string]any{
+				"success": false,
+				"error":   "area_id must be a positive integer",
+			})
+			return
+		}
 	}
-	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+	page, pageSize, err := parsePage(query)
+	if err != nil {
 		respondJSON(w, http.StatusBadRequest, map[string]any{
 			"success": false,
-			"error":   "invalid request body " + err.Error(),
+			"error":   err.Error(),
 		})
 		return
 	}
+
 	userId, err := h.getUserId(req)
 	if err != nil {
 		respondJSON(w, http.StatusInternalServerError, map[string]any{
@@ -1005,7 +980,7 @@ func (h *AreaHandler) HandleDeleteArea(w http.ResponseWriter, req *http.Request)
 		})
 		return
 	}
-	area, err := h.areaService.GetArea(body.AreaId)
+	letters, total, err := h.reactionQueue.DeadLetters(userId, areaId, page, pageSize)
 	if err != nil {
 		respondJSON(w, http.StatusInternalServerError, map[string]any{
 			"success": false,
@@ -1013,15 +988,717 @@ func (h *AreaHandler) HandleDeleteArea(w http.ResponseWriter, req *http.Request)
 		})
 		return
 	}
-	log.Printf("Deleting area of %d by user %d", area.UserID, userId)
-	if area.UserID != userId {
-		respondJSON(w, http.StatusForbidden, map[string]any{
-			"success": false,
-			"error":   "You are not allowed to delete this area",
-		})
-		return
-	}
-	for _, action := range area.Actions {
+	respondJSON(w, http.StatusOK, map[string]any{
+		"success": true,
+		"data": map[string]any{
+			"dead_letters": letters,
+			"page":         page,
+			"page_size":    pageSize,
+			"total":        total,
+		},
+	})
+}
+
+func (h *AreaHandler) HandleReplayDeadLetter(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		respondJSON(w, http.StatusMethodNotAllowed, map[string]any{
+			"success": false,
+			"error":   "method not allowed",
+		})
+		return
+	}
+	var body struct {
+		DeadLetterId int `json:"dead_letter_id"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.DeadLetterId < 1 {
+		respondJSON(w, http.StatusBadRequest, map[string]any{
+			"success": false,
+			"error":   "invalid request body",
+		})
+		return
+	}
+	userId, err := h.getUserId(req)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   "Error getting user ID," + err.Error(),
+		})
+		return
+	}
+	job, err := h.reactionQueue.Replay(userId, body.DeadLetterId)
+	if errors.Is(err, service.ErrDeadLetterNotFound) {
+		respondJSON(w, http.StatusNotFound, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	respondJSON(w, http.StatusAccepted, map[string]any{
+		"success": true,
+		"data": map[string]any{
+			"execution_id": job.ExecutionID,
+			"job_id":       job.ID,
+		},
+	})
+}
+
+type testAreaRequest struct {
+	AreaID       int                 `json:"area_id"`
+	Area         *domain.Area        `json:"area"`
+	OutputFields []domain.InputField `json:"output_fields"`
+	Live         bool                `json:"live"`
+}
+
+// HandleTestArea renders the reactions of a saved area, or of an unsaved area
+// definition, with sample output fields and returns the requests a trigger
+// would send. With live set the requests are sent too.
+func (h *AreaHandler) HandleTestArea(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		respondJSON(w, http.StatusMethodNotAllowed, map[string]any{
+			"success": false,
+			"error":   "method not allowed",
+		})
+		return
+	}
+	var body testAreaRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || (body.AreaID == 0 && body.Area == nil) {
+		respondJSON(w, http.StatusBadRequest, map[string]any{
+			"success": false,
+			"error":   "invalid request body, area_id or area is required",
+		})
+		return
+	}
+	userId, err := h.getUserId(req)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   "Error getting user ID," + err.Error(),
+		})
+		return
+	}
+
+	var area domain.Area
+	if body.AreaID != 0 {
+		area, err = h.areaService.GetArea(body.AreaID)
+		if err != nil {
+			respondJSON(w, http.StatusNotFound, map[string]any{
+				"success": false,
+				"error":   "area not found",
+			})
+			return
+		}
+		if area.UserID != userId {
+			respondJSON(w, http.StatusForbidden, map[string]any{
+				"success": false,
+				"error":   "You are not allowed to test this area",
+			})
+			return
+		}
+	} else {
+		area = *body.Area
+		area.UserID = userId
+		areaConfig, err := h.getAreaConfiguration(area)
+		if err != nil {
+			respondJSON(w, http.StatusInternalServerError, map[string]any{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		if err := CheckAreaValidity(area, areaConfig); err != nil {
+			respondJSON(w, http.StatusBadRequest, map[string]any{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]any{
+		"success": true,
+		"data": map[string]any{
+			"area_id":   area.ID,
+			"live":      body.Live,
+			"reactions": h.dryRunReactions(area, body.OutputFields, body.Live),
+		},
+	})
+}
+
+// dryRunReactions renders the reactions of the area as a trigger with the
+// output fields would. When live, the requests are sent in order, so chained
+// reactions get the outputs of the earlier ones; nothing is recorded in the
+// history. Otherwise the references to earlier outputs are left as is.
+func (h *AreaHandler) dryRunReactions(area domain.Area, outputFields []domain.InputField, live bool) []domain.ReactionDryRun {
+	results := make([]domain.ReactionDryRun, 0, len(area.Reactions))
+	outputs := make(map[int][]domain.InputField)
+	for i, reaction := range area.Reactions {
+		result := domain.ReactionDryRun{
+			ReactionID: reaction.ID,
+			Position:   i,
+			Service:    reaction.Service,
+			Title:      reaction.Title,
+		}
+		if !service.MatchCondition(reaction.Condition, outputFields) {
+			result.Skipped = true
+			results = append(results, result)
+			continue
+		}
+
+		fields := outputFields
+		if live && service.ChainsReactions(reaction) {
+			chained, err := service.ChainedFields(reaction, outputs)
+			if err != nil {
+				result.Error = err.Error()
+				results = append(results, result)
+				continue
+			}
+			fields = append(append([]domain.InputField{}, outputFields...), chained...)
+		}
+		reactionConfig, fieldValues, userToken, err := h.prepareReaction(reaction, fields, area.UserID)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		request, err := h.areaService.PreviewReaction(userToken, fieldValues, reactionConfig)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		result.Request = &request
+
+		if live {
+			execution, err := h.areaService.LaunchReaction("", userToken, fieldValues, reactionConfig)
+			execution.ReactionID = reaction.ID
+			execution.Attempt = 1
+			result.Result = &execution
+			if err == nil {
+				outputs[i] = execution.Outputs
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// HandleExportAreas returns the area given by area_id, or all the areas of the
+// user, as a versioned export document without secrets.
+func (h *AreaHandler) HandleExportAreas(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		respondJSON(w, http.StatusMethodNotAllowed, map[string]any{
+			"success": false,
+			"error":   "method not allowed",
+		})
+		return
+	}
+	userId, err := h.getUserId(req)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   "Error getting user ID," + err.Error(),
+		})
+		return
+	}
+
+	var areas []domain.Area
+	if rawAreaId := req.URL.Query().Get("area_id"); rawAreaId != "" {
+		areaId, err := strconv.Atoi(rawAreaId)
+		if err != nil {
+			respondJSON(w, http.StatusBadRequest, map[string]any{
+				"success": false,
+				"error":   "invalid area_id",
+			})
+			return
+		}
+		area, err := h.areaService.GetArea(areaId)
+		if err != nil {
+			respondJSON(w, http.StatusNotFound, map[string]any{
+				"success": false,
+				"error":   "area not found",
+			})
+			return
+		}
+		if area.UserID != userId {
+			respondJSON(w, http.StatusForbidden, map[string]any{
+				"success": false,
+				"error":   "You are not allowed to export this area",
+			})
+			return
+		}
+		areas = append(areas, area)
+	} else {
+		areas, err = h.areaService.GetUserAreas(userId)
+		if err != nil {
+			respondJSON(w, http.StatusInternalServerError, map[string]any{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
+	exported := make([]domain.ExportedArea, 0, len(areas))
+	for _, area := range areas {
+		areaConfig, err := h.getAreaConfiguration(area)
+		if err != nil {
+			respondJSON(w, http.StatusInternalServerError, map[string]any{
+				"success": false,
+				"error":   fmt.Sprintf("area %s: %v", area.Name, err),
+			})
+			return
+		}
+		exported = append(exported, service.ExportArea(area, areaConfig))
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"success": true,
+		"data":    service.NewAreaExport(exported),
+	})
+}
+
+// HandleImportAreas creates the areas of an export document for the user,
+// inactive. Nothing is created unless every area is valid against the current
+// service configs, and the areas are saved in one transaction. Their actions
+// are then registered with the action services; when that fails the areas stay
+// imported and are returned with the error.
+func (h *AreaHandler) HandleImportAreas(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		respondJSON(w, http.StatusMethodNotAllowed, map[string]any{
+			"success": false,
+			"error":   "method not allowed",
+		})
+		return
+	}
+	var document domain.AreaExport
+	if err := json.NewDecoder(req.Body).Decode(&document); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]any{
+			"success": false,
+			"error":   "invalid request body " + err.Error(),
+		})
+		return
+	}
+	if err := service.CheckAreaExport(document); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	userId, err := h.getUserId(req)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   "Error getting user ID," + err.Error(),
+		})
+		return
+	}
+
+	areas := make([]domain.Area, 0, len(document.Areas))
+	var invalid []string
+	var everything domain.Area
+	for i, exported := range document.Areas {
+		area := service.ImportArea(exported, userId)
+		areaConfig, err := h.getAreaConfiguration(area)
+		if err == nil {
+			err = CheckAreaValidity(area, areaConfig)
+		}
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("areas.%d (%s): %v", i, exported.Name, err))
+			continue
+		}
+		areas = append(areas, area)
+		everything.Actions = append(everything.Actions, area.Actions...)
+		everything.Reactions = append(everything.Reactions, area.Reactions...)
+	}
+	if len(invalid) > 0 {
+		respondJSON(w, http.StatusBadRequest, map[string]any{
+			"success": false,
+			"error":   "some areas are invalid, nothing was imported",
+			"errors":  invalid,
+		})
+		return
+	}
+
+	missingProviders, err := h.checkUserProviderConnections(userId, everything)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   "Error checking provider connections: " + err.Error(),
+		})
+		return
+	}
+
+	imported, err := h.areaService.SaveAreas(areas)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   "Error saving areas, nothing was imported: " + err.Error(),
+		})
+		return
+	}
+	for _, area := range imported {
+		if err := h.TriggerAction(area.Actions, area.Active, req.Header.Get("Authorization")); err != nil {
+			respondJSON(w, http.StatusInternalServerError, map[string]any{
+				"success":  false,
+				"error":    fmt.Sprintf("area %s: %v", area.Name, err),
+				"imported": imported,
+			})
+			return
+		}
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"success": true,
+		"data": map[string]any{
+			"areas":             imported,
+			"missing_providers": missingProviders,
+		},
+	})
+}
+
+func checkFieldsValidity(fields []domain.InputField, config []domain.FieldConfig) error {
+	for _, field := range config {
+		if field.Required {
+			var found bool
+			for _, inputField := range fields {
+				if inputField.Name == field.Name && inputField.Value != "" {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("field %s is required", field.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func CheckAreaValidity(area domain.Area, config domain.AreaConfig) error {
+	for i, action := range area.Actions {
+		configAction := config.Actions[i]
+		err := checkFieldsValidity(action.Input, configAction.Fields)
+		if err != nil {
+			return err
+		}
+	}
+	outputs := make(map[string]bool)
+	for _, configAction := range config.Actions {
+		for _, output := range configAction.OutputFields {
+			outputs[output.Name] = true
+		}
+	}
+	for i, reaction := range area.Reactions {
+		configReaction := config.Reactions[i]
+		err := checkFieldsValidity(reaction.Input, configReaction.Fields)
+		if err != nil {
+			return err
+		}
+		if err := service.ValidateCondition(reaction.Condition, outputs); err != nil {
+			return fmt.Errorf("reaction %s: %w", reaction.Title, err)
+		}
+		for _, input := range reaction.Input {
+			if err := service.ValidateTemplate(input.Value); err != nil {
+				return fmt.Errorf("reaction %s: field %s: %w", reaction.Title, input.Name, err)
+			}
+		}
+		if err := service.ValidateReactionReferences(i, reaction, config.Reactions); err != nil {
+			return fmt.Errorf("reaction %s: %w", reaction.Title, err)
+		}
+	}
+	return nil
+}
+
+func (h *AreaHandler) TriggerReaction(requestID string, areaReaction domain.AreaReaction, outputFields []domain.InputField, userId int) (domain.ReactionExecution, error) {
+	failed := domain.ReactionExecution{ReactionID: areaReaction.ID}
+	reactionConfig, fieldValues, userToken, err := h.prepareReaction(areaReaction, outputFields, userId)
+	if err != nil {
+		failed.Error = err.Error()
+		return failed, err
+	}
+	result, err := h.areaService.LaunchReaction(requestID, userToken, fieldValues, reactionConfig)
+	result.ReactionID = areaReaction.ID
+	return result, err
+}
+
+// prepareReaction fetches the reaction config and the user provider profile,
+// and renders the reaction inputs with the output fields. It returns the
+// config, the field values and the user token to launch the reaction with.
+func (h *AreaHandler) prepareReaction(areaReaction domain.AreaReaction, outputFields []domain.InputField, userId int) (domain.ReactionConfig, map[string]string, string, error) {
+	serviceProfile := domain.UserService{}
+	var err error
+	if strings.TrimSpace(areaReaction.Provider) != "" {
+		serviceProfile, err = h.getUserServiceProfile(userId, areaReaction.Provider)
+		if err != nil {
+			return domain.ReactionConfig{}, nil, "", err
+		}
+	}
+	reactionConfig, err := h.getReactionDetails(areaReaction)
+	if err != nil {
+		return domain.ReactionConfig{}, nil, "", err
+	}
+
+	values := make(map[string]string)
+	if strings.TrimSpace(areaReaction.Provider) != "" {
+		for _, serviceField := range serviceProfile.Fields {
+			values[serviceField.FieldKey] = serviceField.StringValue
+		}
+	}
+	for _, outputField := range outputFields {
+		values[outputField.Name] = outputField.Value
+	}
+	lookup := func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+
+	fieldValues := make(map[string]string)
+	for _, field := range areaReaction.Input {
+		value, err := service.RenderTemplate(field.Value, lookup, false)
+		if err != nil {
+			return domain.ReactionConfig{}, nil, "", err
+		}
+		fieldValues[field.Name] = value
+	}
+	if strings.TrimSpace(areaReaction.Provider) != "" {
+		for _, serviceField := range serviceProfile.Fields {
+			fieldValues[serviceField.FieldKey] = serviceField.StringValue
+		}
+	}
+	userToken := ""
+	if strings.TrimSpace(areaReaction.Provider) != "" {
+		userToken = serviceProfile.Profile.AccessToken
+	}
+	return reactionConfig, fieldValues, userToken, nil
+}
+
+type actionRequest struct {
+	Active   bool                `json:"active"`
+	ActionID int                 `json:"action_id"`
+	Type     string              `json:"type"`
+	Provider string              `json:"provider"`
+	Service  string              `json:"service"`
+	Title    string              `json:"title"`
+	Input    []domain.InputField `json:"input"`
+}
+
+func (h *AreaHandler) TriggerAction(areaAction []domain.AreaAction, isActive bool, authHeader string) error {
+	cronActions := make([]domain.AreaAction, 0)
+	otherActions := make([]domain.AreaAction, 0)
+	for _, action := range areaAction {
+		action.Active = isActive
+		if action.Type == "cron" {
+			cronActions = append(cronActions, action)
+		} else {
+			otherActions = append(otherActions, action)
+		}
+	}
+	if isActive {
+		for _, action := range cronActions {
+			err := h.TriggerCronAction(action)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	actionsByType := make(map[string][]actionRequest)
+	for _, action := range otherActions {
+		action.Active = isActive
+		actionsByType[action.Type] = append(actionsByType[action.Type], actionRequest{
+			Active:   action.Active,
+			ActionID: action.ID,
+			Type:     action.Type,
+			Provider: action.Provider,
+			Service:  action.Service,
+			Title:    action.Title,
+			Input:    action.Input,
+		})
+	}
+
+	for actionType, actions := range actionsByType {
+		createUrl, exist := h.cfg.CreateActionsUrls[actionType]
+		if !exist {
+			return fmt.Errorf("action type %s not supported", actionType)
+		}
+		var body struct {
+			Actions []actionRequest `json:"actions"`
+		}
+		body.Actions = actions
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		resp, err := http.NewRequest(http.MethodPost, createUrl, bytes.NewBuffer(payload))
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(authHeader) != "" {
+			resp.Header.Set("Authorization", authHeader)
+		} else if h.cfg.InternalSecret != "" {
+			resp.Header.Set("Authorization", "Bearer "+h.cfg.InternalSecret)
+		}
+		if h.cfg.InternalSecret != "" {
+			resp.Header.Set("X-Internal-Secret", h.cfg.InternalSecret)
+		}
+		client := &http.Client{}
+		_, err = client.Do(resp)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *AreaHandler) TriggerCronAction(areaAction domain.AreaAction) error {
+	if areaAction.Type != "cron" {
+		return fmt.Errorf("only cron actions are supported")
+	}
+	var delay int
+	outputFields := make([]domain.InputField, 0, len(areaAction.Input))
+	for _, input := range areaAction.Input {
+		if input.Name == "delay" {
+			delay, _ = strconv.Atoi(input.Value)
+		}
+		outputFields = append(outputFields, domain.InputField{Name: input.Name, Value: input.Value})
+	}
+	log.Printf("Triggering action %d in %d seconds", areaAction.ID, delay)
+	_, err := h.scheduler.ScheduleCronAction(areaAction.ID, outputFields, time.Duration(delay)*time.Second)
+	return err
+}
+
+// RunDelayedTrigger runs a delayed trigger once it is due, for the scheduler.
+func (h *AreaHandler) RunDelayedTrigger(trigger domain.DelayedTrigger) error {
+	switch trigger.Kind {
+	case domain.DelayedCronAction:
+		var outputFields []domain.InputField
+		if err := json.Unmarshal(trigger.Payload, &outputFields); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+		return h.triggerCronArea(trigger.ActionID, outputFields)
+	case domain.DelayedCalendarEvent:
+		var payload domain.CalendarEventPayload
+		if err := json.Unmarshal(trigger.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+		token, err := h.getUserServiceToken(payload.UserID, "google")
+		if err != nil {
+			return fmt.Errorf("getting the Google token of user %d: %w", payload.UserID, err)
+		}
+		event, err := h.areaService.CreateCalendarEvent(token, payload.Event)
+		if err != nil {
+			return err
+		}
+		log.Printf("Delayed calendar event created successfully: %s", event.Summary)
+		return nil
+	default:
+		return fmt.Errorf("unknown delayed trigger kind %s", trigger.Kind)
+	}
+}
+
+// triggerCronArea fires the area of a cron action through /triggerArea, as
+// the action engines do.
+func (h *AreaHandler) triggerCronArea(actionId int, outputFields []domain.InputField) error {
+	var body struct {
+		OutputFields []domain.InputField `json:"output_fields"`
+		ActionId     int                 `json:"action_id"`
+	}
+	body.OutputFields = outputFields
+	body.ActionId = actionId
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	endpoint := strings.TrimRight(h.cfg.AreaServiceURL, "/") + "/triggerArea"
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(payload))
+	if err != nil {
+		return err
+	}
+	if h.cfg.InternalSecret != "" {
+		req.Header.Set("X-Internal-Secret", h.cfg.InternalSecret)
+	}
+	req.Header.Set(service.RequestIDHeader, service.NewRequestID())
+	resp, err := triggerClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("triggerArea returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (h *AreaHandler) HandleDeleteArea(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		respondJSON(w, http.StatusMethodNotAllowed, map[string]any{
+			"success": false,
+			"error":   "method not allowed",
+		})
+		return
+	}
+	var body struct {
+		AreaId int `json:"area_id"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]any{
+			"success": false,
+			"error":   "invalid request body " + err.Error(),
+		})
+		return
+	}
+	userId, err := h.getUserId(req)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   "Error getting user ID," + err.Error(),
+		})
+		return
+	}
+	area, err := h.areaService.GetArea(body.AreaId)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	log.Printf("Deleting area of %d by user %d", area.UserID, userId)
+	if area.UserID != userId {
+		respondJSON(w, http.StatusForbidden, map[string]any{
+			"success": false,
+			"error":   "You are not allowed to delete this area",
+		})
+		return
+	}
+	if err := h.unregisterActions(req, area.Actions); err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	err = h.areaService.DeleteArea(body.AreaId)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{})
+}
+
+// unregisterActions removes the actions from their trigger service, when that
+// service keeps a registration.
+func (h *AreaHandler) unregisterActions(req *http.Request, actions []domain.AreaAction) error {
+	for _, action := range actions {
 		delUrl, exist := h.cfg.DelActionsUrls[action.Type]
 		if !exist || delUrl == "nil" {
 			continue
@@ -1029,11 +1706,7 @@ func (h *AreaHandler) HandleDeleteArea(w http.ResponseWriter, req *http.Request)
 		deleteUrl := delUrl + "/" + strconv.Itoa(action.ID)
 		delReq, err := http.NewRequest(http.MethodDelete, deleteUrl, nil)
 		if err != nil {
-			respondJSON(w, http.StatusInternalServerError, map[string]any{
-				"success": false,
-				"error":   err.Error(),
-			})
-			return
+			return err
 		}
 		if authHeader := req.Header.Get("Authorization"); authHeader != "" {
 			delReq.Header.Set("Authorization", authHeader)
@@ -1044,14 +1717,56 @@ func (h *AreaHandler) HandleDeleteArea(w http.ResponseWriter, req *http.Request)
 		client := &http.Client{}
 		_, err = client.Do(delReq)
 		if err != nil {
-			respondJSON(w, http.StatusInternalServerError, map[string]any{
-				"success": false,
-				"error":   err.Error(),
-			})
-			return
+			return err
 		}
 	}
-	err = h.areaService.DeleteArea(body.AreaId)
+	return nil
+}
+
+func (h *AreaHandler) HandleUpdateArea(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		respondJSON(w, http.StatusMethodNotAllowed, map[string]any{
+			"success": false,
+			"error":   "method not allowed",
+		})
+		return
+	}
+	var body domain.Area
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]any{
+			"success": false,
+			"error":   "invalid request body " + err.Error(),
+		})
+		return
+	}
+	userId, err := h.getUserId(req)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   "Error getting user ID," + err.Error(),
+		})
+		return
+	}
+	current, err := h.areaService.GetArea(body.ID)
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]any{
+			"success": false,
+			"error":   "area not found",
+		})
+		return
+	}
+	if current.UserID != userId {
+		respondJSON(w, http.StatusForbidden, map[string]any{
+			"success": false,
+			"error":   "You are not allowed to update this area",
+		})
+		return
+	}
+	// activation goes through /activateArea and /deactivateArea
+	body.UserID = userId
+	body.Active = current.Active
+
+	areaConfig, err := h.getAreaConfiguration(body)
 	if err != nil {
 		respondJSON(w, http.StatusInternalServerError, map[string]any{
 			"success": false,
@@ -1059,7 +1774,83 @@ func (h *AreaHandler) HandleDeleteArea(w http.ResponseWriter, req *http.Request)
 		})
 		return
 	}
-	respondJSON(w, http.StatusOK, map[string]any{})
+	if err := CheckAreaValidity(body, areaConfig); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	missingProviders, err := h.checkUserProviderConnections(userId, body)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   "Error checking provider connections: " + err.Error(),
+		})
+		return
+	}
+
+	area, diff, err := h.areaService.UpdateArea(current, body)
+	if errors.Is(err, service.ErrUnknownAreaItem) {
+		respondJSON(w, http.StatusBadRequest, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// the stored versions of the updated actions are dropped, then registered again
+	previous := make(map[int]domain.AreaAction, len(current.Actions))
+	for _, action := range current.Actions {
+		previous[action.ID] = action
+	}
+	stale := append([]domain.AreaAction{}, diff.DeletedActions...)
+	for _, action := range diff.UpdatedActions {
+		stale = append(stale, previous[action.ID])
+	}
+	if err := h.unregisterActions(req, stale); err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	register := append(append([]domain.AreaAction{}, diff.CreatedActions...), diff.UpdatedActions...)
+	if len(register) > 0 {
+		if err := h.TriggerAction(register, area.Active, req.Header.Get("Authorization")); err != nil {
+			respondJSON(w, http.StatusInternalServerError, map[string]any{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
+	response := map[string]any{
+		"success": true,
+		"data":    area,
+		"changes": map[string]int{
+			"actions_created":   len(diff.CreatedActions),
+			"actions_updated":   len(diff.UpdatedActions),
+			"actions_deleted":   len(diff.DeletedActions),
+			"reactions_created": len(diff.CreatedReactions),
+			"reactions_updated": len(diff.UpdatedReactions),
+			"reactions_deleted": len(diff.DeletedReactions),
+		},
+	}
+	if len(missingProviders) > 0 {
+		response["message"] = "Area updated but some provider connections are missing; actions may not run until you connect them."
+		response["missing_providers"] = missingProviders
+	}
+	respondJSON(w, http.StatusOK, response)
 }
 
 func (h *AreaHandler) HandleDeactivateAreasByProvider(w http.ResponseWriter, req *http.Request) {
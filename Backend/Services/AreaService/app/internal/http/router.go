@@ -25,9 +25,16 @@ func (r *Router) routes() {
 	r.mux.HandleFunc("/createEvent", r.areaHandler.HandleCreateEventArea)
 	r.mux.HandleFunc("/saveArea", r.areaHandler.SaveArea)
 	r.mux.HandleFunc("/getAreas", r.areaHandler.GetAreas)
+	r.mux.HandleFunc("/getAreaExecutions", r.areaHandler.HandleGetAreaExecutions)
 	r.mux.HandleFunc("/triggerArea", r.areaHandler.HandleActionTrigger)
+	r.mux.HandleFunc("/getDeadLetters", r.areaHandler.HandleGetDeadLetters)
+	r.mux.HandleFunc("/replayDeadLetter", r.areaHandler.HandleReplayDeadLetter)
+	r.mux.HandleFunc("/testArea", r.areaHandler.HandleTestArea)
+	r.mux.HandleFunc("/exportAreas", r.areaHandler.HandleExportAreas)
+	r.mux.HandleFunc("/importAreas", r.areaHandler.HandleImportAreas)
 	r.mux.HandleFunc("/activateArea", r.areaHandler.HandleActivateArea)
 	r.mux.HandleFunc("/deactivateArea", r.areaHandler.HandleDeactivateArea)
+	r.mux.HandleFunc("/updateArea", r.areaHandler.HandleUpdateArea)
 	r.mux.HandleFunc("/deleteArea", r.areaHandler.HandleDeleteArea)
 	r.mux.HandleFunc("/deactivateAreasByProvider", r.areaHandler.HandleDeactivateAreasByProvider)
 }
@@ -74,26 +74,66 @@ func (a areaRepository) GetAreaFromAction(actionId int) (domain.Area, error) {
 }
 
 func (a areaRepository) SaveReactions(areaID int, reactions []domain.AreaReaction) ([]domain.AreaReaction, error) {
-	for _, reaction := range reactions {
-		inputJSON, err := json.Marshal(reaction.Input)
-		if err != nil {
-			return reactions, err
-		}
-		err = a.db.QueryRow(`INSERT INTO reactions (area_id, provider, service, title, inputs) VALUES ($1, $2, $3, $4, $5) RETURNING id`, areaID, reaction.Provider, reaction.Service, reaction.Title, inputJSON).Scan(&reaction.ID)
+	return insertReactions(a.db, areaID, reactions)
+}
+
+func (a areaRepository) SaveActions(areaID int, actions []domain.AreaAction) ([]domain.AreaAction, error) {
+	return insertActions(a.db, areaID, actions)
+}
+
+func (a areaRepository) SaveArea(area domain.Area) (domain.Area, error) {
+	return insertArea(a.db, area)
+}
+
+// SaveAreas saves all the areas or none of them.
+func (a areaRepository) SaveAreas(areas []domain.Area) ([]domain.Area, error) {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	saved := make([]domain.Area, 0, len(areas))
+	for _, area := range areas {
+		area, err = insertArea(tx, area)
 		if err != nil {
-			return reactions, err
+			return nil, err
 		}
+		saved = append(saved, area)
 	}
-	return reactions, nil
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return saved, nil
 }
 
-func (a areaRepository) SaveActions(areaID int, actions []domain.AreaAction) ([]domain.AreaAction, error) {
+func insertArea(q rowQuerier, area domain.Area) (domain.Area, error) {
+	var areaID int
+	err := q.QueryRow(`INSERT INTO areas (name, active, user_id) VALUES ($1, $2, $3) RETURNING id`, area.Name, area.Active, area.UserID).Scan(&areaID)
+	if err != nil {
+		return area, err
+	}
+	area.ID = areaID
+	actions, err := insertActions(q, areaID, area.Actions)
+	if err != nil {
+		return area, err
+	}
+	area.Actions = actions
+	reactions, err := insertReactions(q, areaID, area.Reactions)
+	if err != nil {
+		return area, err
+	}
+	area.Reactions = reactions
+	return area, nil
+}
+
+func insertActions(q rowQuerier, areaID int, actions []domain.AreaAction) ([]domain.AreaAction, error) {
 	for i, action := range actions {
 		inputJSON, err := json.Marshal(action.Input)
 		if err != nil {
 			return actions, err
 		}
-		err = a.db.QueryRow(`INSERT INTO actions (area_id, provider, service, title, inputs, type) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`, areaID, action.Provider, action.Service, action.Title, inputJSON, action.Type).Scan(&actions[i].ID)
+		err = q.QueryRow(`INSERT INTO actions (area_id, provider, service, title, inputs, type) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`, areaID, action.Provider, action.Service, action.Title, inputJSON, action.Type).Scan(&actions[i].ID)
 		if err != nil {
 			return actions, err
 		}
@@ -101,46 +141,141 @@ func (a areaRepository) SaveActions(areaID int, actions []domain.AreaAction) ([]
 	return actions, nil
 }
 
-func (a areaRepository) SaveArea(area domain.Area) (domain.Area, error) {
-	var areaID int
-	err := a.db.QueryRow(`INSERT INTO areas (name, active, user_id) VALUES ($1, $2, $3) RETURNING id`, area.Name, area.Active, area.UserID).Scan(&areaID)
+func insertReactions(q rowQuerier, areaID int, reactions []domain.AreaReaction) ([]domain.AreaReaction, error) {
+	for i, reaction := range reactions {
+		inputJSON, err := json.Marshal(reaction.Input)
+		if err != nil {
+			return reactions, err
+		}
+		conditionJSON, err := marshalCondition(reaction.Condition)
+		if err != nil {
+			return reactions, err
+		}
+		err = q.QueryRow(`INSERT INTO reactions (area_id, provider, service, title, inputs, condition, position) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`, areaID, reaction.Provider, reaction.Service, reaction.Title, inputJSON, conditionJSON, i).Scan(&reactions[i].ID)
+		if err != nil {
+			return reactions, err
+		}
+	}
+	return reactions, nil
+}
+
+func (a areaRepository) UpdateArea(area domain.Area, diff domain.AreaDiff) (domain.Area, error) {
+	tx, err := a.db.Begin()
 	if err != nil {
 		return area, err
 	}
-	area.ID = areaID
-	actions, err := a.SaveActions(areaID, area.Actions)
-	if err != nil {
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE areas SET name = $1 WHERE id = $2", area.Name, area.ID); err != nil {
 		return area, err
 	}
-	area.Actions = actions
-	reactions, err := a.SaveReactions(areaID, area.Reactions)
-	if err != nil {
+
+	for _, action := range diff.DeletedActions {
+		if _, err := tx.Exec("DELETE FROM actions WHERE id = $1 AND area_id = $2", action.ID, area.ID); err != nil {
+			return area, err
+		}
+	}
+	for _, action := range diff.UpdatedActions {
+		inputJSON, err := json.Marshal(action.Input)
+		if err != nil {
+			return area, err
+		}
+		_, err = tx.Exec(`UPDATE actions SET provider = $1, service = $2, title = $3, inputs = $4, type = $5 WHERE id = $6 AND area_id = $7`, action.Provider, action.Service, action.Title, inputJSON, action.Type, action.ID, area.ID)
+		if err != nil {
+			return area, err
+		}
+	}
+	for _, action := range diff.CreatedActions {
+		inputJSON, err := json.Marshal(action.Input)
+		if err != nil {
+			return area, err
+		}
+		_, err = tx.Exec(`INSERT INTO actions (area_id, provider, service, title, inputs, type) VALUES ($1, $2, $3, $4, $5, $6)`, area.ID, action.Provider, action.Service, action.Title, inputJSON, action.Type)
+		if err != nil {
+			return area, err
+		}
+	}
+
+	for _, reaction := range diff.DeletedReactions {
+		if _, err := tx.Exec("DELETE FROM reactions WHERE id = $1 AND area_id = $2", reaction.ID, area.ID); err != nil {
+			return area, err
+		}
+	}
+	for _, reaction := range diff.UpdatedReactions {
+		inputJSON, err := json.Marshal(reaction.Input)
+		if err != nil {
+			return area, err
+		}
+		conditionJSON, err := marshalCondition(reaction.Condition)
+		if err != nil {
+			return area, err
+		}
+		_, err = tx.Exec(`UPDATE reactions SET provider = $1, service = $2, title = $3, inputs = $4, condition = $5 WHERE id = $6 AND area_id = $7`, reaction.Provider, reaction.Service, reaction.Title, inputJSON, conditionJSON, reaction.ID, area.ID)
+		if err != nil {
+			return area, err
+		}
+	}
+	// The reactions run in the order of the edit, the created ones included.
+	for i, reaction := range area.Reactions {
+		if reaction.ID != 0 {
+			if _, err := tx.Exec("UPDATE reactions SET position = $1 WHERE id = $2 AND area_id = $3", i, reaction.ID, area.ID); err != nil {
+				return area, err
+			}
+			continue
+		}
+		inputJSON, err := json.Marshal(reaction.Input)
+		if err != nil {
+			return area, err
+		}
+		conditionJSON, err := marshalCondition(reaction.Condition)
+		if err != nil {
+			return area, err
+		}
+		_, err = tx.Exec(`INSERT INTO reactions (area_id, provider, service, title, inputs, condition, position) VALUES ($1, $2, $3, $4, $5, $6, $7)`, area.ID, reaction.Provider, reaction.Service, reaction.Title, inputJSON, conditionJSON, i)
+		if err != nil {
+			return area, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
 		return area, err
 	}
-	area.Reactions = reactions
-	return area, nil
+	return a.GetArea(area.ID)
 }
 
 func (a areaRepository) GetAreaReactions(areaID int) ([]domain.AreaReaction, error) {
-	rows, err := a.db.Query("SELECT id, provider, service, title, inputs FROM reactions WHERE area_id = $1", areaID)
+	rows, err := a.db.Query("SELECT id, provider, service, title, inputs, condition FROM reactions WHERE area_id = $1 ORDER BY position, id", areaID)
 	if err != nil {
 		return nil, err
 	}
 	reactions := make([]domain.AreaReaction, 0)
 	for rows.Next() {
 		var reaction domain.AreaReaction
-		var inputJSON []byte
-		if err := rows.Scan(&reaction.ID, &reaction.Provider, &reaction.Service, &reaction.Title, &inputJSON); err != nil {
+		var inputJSON, conditionJSON []byte
+		if err := rows.Scan(&reaction.ID, &reaction.Provider, &reaction.Service, &reaction.Title, &inputJSON, &conditionJSON); err != nil {
 			return nil, err
 		}
 		if err := json.Unmarshal(inputJSON, &reaction.Input); err != nil {
 			return nil, err
 		}
+		if conditionJSON != nil {
+			if err := json.Unmarshal(conditionJSON, &reaction.Condition); err != nil {
+				return nil, err
+			}
+		}
 		reactions = append(reactions, reaction)
 	}
 	return reactions, nil
 }
 
+// marshalCondition stores a reaction without condition as NULL.
+func marshalCondition(condition *domain.ReactionCondition) (any, error) {
+	if condition == nil {
+		return nil, nil
+	}
+	return json.Marshal(condition)
+}
+
 func (a areaRepository) GetAreaActions(areaID int) ([]domain.AreaAction, error) {
 	rows, err := a.db.Query("SELECT id, provider, service, title, inputs, type FROM actions WHERE area_id = $1", areaID)
 	if err != nil {
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// insertDB is a database/sql driver answering every `INSERT ... RETURNING id`
+// with the next id, and recording the statements, the inserts and the
+// transaction outcomes. The insert number failAt fails when it is not 0.
+type insertDB struct {
+	mu         sync.Mutex
+	nextID     int64
+	statements []statement
+	inserts    []string
+	failAt     int
+	commits    int
+	rollbacks  int
+}
+
+type statement struct {
+	query string
+	args  []driver.Value
+}
+
+// find returns the first statement containing all the parts.
+func (d *insertDB) find(parts ...string) (statement, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, stmt := range d.statements {
+		matches := true
+		for _, part := range parts {
+			matches = matches && strings.Contains(stmt.query, part)
+		}
+		if matches {
+			return stmt, true
+		}
+	}
+	return statement{}, false
+}
+
+func (d *insertDB) Connect(context.Context) (driver.Conn, error) { return insertConn{d}, nil }
+func (d *insertDB) Driver() driver.Driver                        { return nil }
+
+type insertConn struct{ db *insertDB }
+
+func (c insertConn) Prepare(query string) (driver.Stmt, error) { return insertStmt{c.db, query}, nil }
+func (c insertConn) Close() error                              { return nil }
+func (c insertConn) Begin() (driver.Tx, error)                 { return insertTx{c.db}, nil }
+
+type insertTx struct{ db *insertDB }
+
+func (t insertTx) Commit() error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+	t.db.commits++
+	return nil
+}
+
+func (t insertTx) Rollback() error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+	t.db.rollbacks++
+	return nil
+}
+
+type insertStmt struct {
+	db    *insertDB
+	query string
+}
+
+func (s insertStmt) Close() error  { return nil }
+func (s insertStmt) NumInput() int { return -1 }
+
+func (s insertStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.statements = append(s.db.statements, statement{s.query, args})
+	return driver.RowsAffected(1), nil
+}
+
+func (s insertStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.statements = append(s.db.statements, statement{s.query, args})
+	if !strings.HasPrefix(s.query, "INSERT INTO ") {
+		return &idRows{columns: []string{"id"}}, nil
+	}
+	table, _, _ := strings.Cut(strings.TrimPrefix(s.query, "INSERT INTO "), " ")
+	s.db.inserts = append(s.db.inserts, table)
+	if len(s.db.inserts) == s.db.failAt {
+		return nil, errors.New("insert failed")
+	}
+	s.db.nextID++
+	_, returning, _ := strings.Cut(s.query, "RETURNING ")
+	return &idRows{columns: strings.Split(returning, ", "), ids: []int64{s.db.nextID}}, nil
+}
+
+// idRows returns the ids, the other returned columns being zero timestamps.
+type idRows struct {
+	columns []string
+	ids     []int64
+}
+
+func (r *idRows) Columns() []string { return r.columns }
+func (r *idRows) Close() error      { return nil }
+
+func (r *idRows) Next(dest []driver.Value) error {
+	if len(r.ids) == 0 {
+		return io.EOF
+	}
+	dest[0] = r.ids[0]
+	for i := 1; i < len(dest); i++ {
+		dest[i] = time.Time{}
+	}
+	r.ids = r.ids[1:]
+	return nil
+}
+
+func TestAreaRepository_SaveArea_ReturnsIDs(t *testing.T) {
+	fake := &insertDB{}
+	repo := NewAreaRepository(sql.OpenDB(fake))
+
+	saved, err := repo.SaveArea(domain.Area{
+		Name:   "area",
+		UserID: 1,
+		Actions: []domain.AreaAction{
+			{Service: "cron", Title: "delay_action"},
+		},
+		Reactions: []domain.AreaReaction{
+			{Service: "discord", Title: "send_webhook_message"},
+			{Service: "notion", Title: "create_page"},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"areas", "actions", "reactions", "reactions"}, fake.inserts)
+	assert.Equal(t, 1, saved.ID)
+	assert.Equal(t, 2, saved.Actions[0].ID)
+	assert.Equal(t, 3, saved.Reactions[0].ID)
+	assert.Equal(t, 4, saved.Reactions[1].ID)
+}
+
+func importedAreas() []domain.Area {
+	return []domain.Area{
+		{
+			Name:      "first",
+			UserID:    1,
+			Actions:   []domain.AreaAction{{Service: "cron", Title: "delay_action"}},
+			Reactions: []domain.AreaReaction{{Service: "discord", Title: "send_webhook_message"}},
+		},
+		{
+			Name:      "second",
+			UserID:    1,
+			Actions:   []domain.AreaAction{{Service: "github", Title: "new_issue"}},
+			Reactions: []domain.AreaReaction{{Service: "notion", Title: "create_page"}},
+		},
+	}
+}
+
+func TestAreaRepository_SaveAreas(t *testing.T) {
+	fake := &insertDB{}
+	repo := NewAreaRepository(sql.OpenDB(fake))
+
+	saved, err := repo.SaveAreas(importedAreas())
+
+	assert.NoError(t, err)
+	assert.Len(t, saved, 2)
+	assert.Equal(t, 1, saved[0].ID)
+	assert.Equal(t, 4, saved[1].ID)
+	assert.Equal(t, 6, saved[1].Reactions[0].ID)
+	assert.Equal(t, 1, fake.commits)
+}
+
+func TestAreaRepository_SaveAreas_RollsBackOnFailure(t *testing.T) {
+	// The reaction of the second area fails to insert.
+	fake := &insertDB{failAt: 6}
+	repo := NewAreaRepository(sql.OpenDB(fake))
+
+	saved, err := repo.SaveAreas(importedAreas())
+
+	assert.Error(t, err)
+	assert.Nil(t, saved)
+	assert.Equal(t, 0, fake.commits)
+	assert.Equal(t, 1, fake.rollbacks)
+}
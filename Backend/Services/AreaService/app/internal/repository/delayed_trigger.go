@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
+)
+
+type delayedTriggerRepository struct {
+	db *sql.DB
+}
+
+func NewDelayedTriggerRepository(db *sql.DB) domain.DelayedTriggerRepository {
+	return &delayedTriggerRepository{db: db}
+}
+
+func (r delayedTriggerRepository) Schedule(trigger domain.DelayedTrigger, delay time.Duration) (domain.DelayedTrigger, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return trigger, err
+	}
+	defer tx.Rollback()
+
+	if trigger.ActionID != 0 {
+		_, err := tx.Exec(
+			"DELETE FROM delayed_triggers WHERE action_id = $1 AND kind = $2 AND status = 'pending'",
+			trigger.ActionID, trigger.Kind,
+		)
+		if err != nil {
+			return trigger, err
+		}
+	}
+	err = tx.QueryRow(
+		`INSERT INTO delayed_triggers (kind, action_id, payload, due_at)
+		 VALUES ($1, NULLIF($2::INTEGER, 0), $3, NOW() + $4 * INTERVAL '1 millisecond')
+		 RETURNING id, due_at, status, created_at`,
+		trigger.Kind, trigger.ActionID, []byte(trigger.Payload), delay.Milliseconds(),
+	).Scan(&trigger.ID, &trigger.DueAt, &trigger.Status, &trigger.CreatedAt)
+	if err != nil {
+		return trigger, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return trigger, err
+	}
+	return trigger, nil
+}
+
+func (r delayedTriggerRepository) Claim(staleAfter time.Duration) (*domain.DelayedTrigger, error) {
+	var trigger domain.DelayedTrigger
+	var payload []byte
+	err := r.db.QueryRow(
+		`UPDATE delayed_triggers SET status = 'running', locked_at = NOW(), attempts = attempts + 1
+		 WHERE id = (
+		   SELECT id FROM delayed_triggers
+		   WHERE (status = 'pending' AND due_at <= NOW())
+		      OR (status = 'running' AND locked_at < NOW() - $1 * INTERVAL '1 millisecond')
+		   ORDER BY due_at
+		   FOR UPDATE SKIP LOCKED
+		   LIMIT 1
+		 )
+		 RETURNING id, kind, COALESCE(action_id, 0), payload, due_at, status, attempts, last_error, created_at`,
+		staleAfter.Milliseconds(),
+	).Scan(&trigger.ID, &trigger.Kind, &trigger.ActionID, &payload, &trigger.DueAt, &trigger.Status, &trigger.Attempts, &trigger.LastError, &trigger.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	trigger.Payload = payload
+	return &trigger, nil
+}
+
+func (r delayedTriggerRepository) Complete(id int) error {
+	_, err := r.db.Exec("DELETE FROM delayed_triggers WHERE id = $1", id)
+	return err
+}
+
+func (r delayedTriggerRepository) Fail(id int, lastError string) error {
+	_, err := r.db.Exec(
+		`UPDATE delayed_triggers SET status = 'failed', locked_at = NULL, last_error = $1, failed_at = NOW()
+		 WHERE id = $2`,
+		lastError, id,
+	)
+	return err
+}
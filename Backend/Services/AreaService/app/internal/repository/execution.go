@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
+)
+
+type executionRepository struct {
+	db *sql.DB
+}
+
+func NewExecutionRepository(db *sql.DB) domain.ExecutionRepository {
+	return &executionRepository{db: db}
+}
+
+func (e executionRepository) Create(execution domain.Execution) (domain.Execution, error) {
+	tx, err := e.db.Begin()
+	if err != nil {
+		return execution, err
+	}
+	defer tx.Rollback()
+
+	execution, err = insertExecution(tx, execution)
+	if err != nil {
+		return execution, err
+	}
+	for i, reaction := range execution.Reactions {
+		execution.Reactions[i].ID, err = insertReactionExecution(tx, execution.ID, reaction)
+		if err != nil {
+			return execution, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return execution, err
+	}
+	return execution, nil
+}
+
+func (e executionRepository) AddReaction(executionID int, reaction domain.ReactionExecution) error {
+	_, err := insertReactionExecution(e.db, executionID, reaction)
+	return err
+}
+
+// rowQuerier is implemented by both *sql.DB and *sql.Tx.
+type rowQuerier interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func insertExecution(tx rowQuerier, execution domain.Execution) (domain.Execution, error) {
+	outputJSON, err := json.Marshal(execution.OutputFields)
+	if err != nil {
+		return execution, err
+	}
+	err = tx.QueryRow(
+		`INSERT INTO executions (area_id, action_id, request_id, output_fields, status, error, started_at, duration_ms)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		execution.AreaID, execution.ActionID, execution.RequestID, outputJSON, execution.Status, execution.Error, execution.StartedAt, execution.DurationMs,
+	).Scan(&execution.ID)
+	return execution, err
+}
+
+func insertReactionExecution(tx rowQuerier, executionID int, reaction domain.ReactionExecution) (int, error) {
+	attempt := reaction.Attempt
+	if attempt < 1 {
+		attempt = 1
+	}
+	var outputsJSON any
+	if reaction.Outputs != nil {
+		encoded, err := json.Marshal(reaction.Outputs)
+		if err != nil {
+			return 0, err
+		}
+		outputsJSON = encoded
+	}
+	var id int
+	err := tx.QueryRow(
+		`INSERT INTO reaction_executions (execution_id, reaction_id, attempt, skipped, method, url, request_body, status_code, response_snippet, duration_ms, error, outputs)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id`,
+		executionID, reaction.ReactionID, attempt, reaction.Skipped, reaction.Method, reaction.URL, reaction.RequestBody, reaction.StatusCode, reaction.ResponseSnippet, reaction.DurationMs, reaction.Error, outputsJSON,
+	).Scan(&id)
+	return id, err
+}
+
+// settleExecution sets the final status of the execution once none of its
+// jobs is left: failed when one of them ended in the dead letters.
+func settleExecution(tx *sql.Tx, executionID int) error {
+	_, err := tx.Exec(
+		`UPDATE executions SET
+		   status = CASE WHEN EXISTS (SELECT 1 FROM reaction_dead_letters WHERE execution_id = $1) THEN 'failed' ELSE 'success' END,
+		   error = CASE WHEN EXISTS (SELECT 1 FROM reaction_dead_letters WHERE execution_id = $1) THEN 'some reactions failed' ELSE '' END,
+		   duration_ms = (EXTRACT(EPOCH FROM (NOW() - started_at)) * 1000)::BIGINT
+		 WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM reaction_jobs WHERE execution_id = $1)`,
+		executionID,
+	)
+	return err
+}
+
+// ListByArea returns a page of the area executions, most recent first, and the
+// total number of executions of the area.
+func (e executionRepository) ListByArea(areaID int, limit int, offset int) ([]domain.Execution, int, error) {
+	var total int
+	if err := e.db.QueryRow("SELECT COUNT(*) FROM executions WHERE area_id = $1", areaID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := e.db.Query(
+		`SELECT id, area_id, action_id, request_id, output_fields, status, error, started_at, duration_ms
+		 FROM executions WHERE area_id = $1
+		 ORDER BY started_at DESC, id DESC
+		 LIMIT $2 OFFSET $3`,
+		areaID, limit, offset,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	executions := make([]domain.Execution, 0)
+	byID := make(map[int]int)
+	for rows.Next() {
+		var execution domain.Execution
+		var outputJSON []byte
+		if err := rows.Scan(&execution.ID, &execution.AreaID, &execution.ActionID, &execution.RequestID, &outputJSON, &execution.Status, &execution.Error, &execution.StartedAt, &execution.DurationMs); err != nil {
+			return nil, 0, err
+		}
+		if err := json.Unmarshal(outputJSON, &execution.OutputFields); err != nil {
+			return nil, 0, err
+		}
+		execution.Reactions = make([]domain.ReactionExecution, 0)
+		byID[execution.ID] = len(executions)
+		executions = append(executions, execution)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(executions) == 0 {
+		return executions, total, nil
+	}
+
+	ids := make([]int64, 0, len(executions))
+	for _, execution := range executions {
+		ids = append(ids, int64(execution.ID))
+	}
+	reactionRows, err := e.db.Query(
+		`SELECT id, execution_id, reaction_id, attempt, skipped, method, url, request_body, status_code, response_snippet, duration_ms, error, outputs
+		 FROM reaction_executions WHERE execution_id = ANY($1)
+		 ORDER BY id`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer reactionRows.Close()
+
+	for reactionRows.Next() {
+		var reaction domain.ReactionExecution
+		var executionID int
+		var outputsJSON []byte
+		if err := reactionRows.Scan(&reaction.ID, &executionID, &reaction.ReactionID, &reaction.Attempt, &reaction.Skipped, &reaction.Method, &reaction.URL, &reaction.RequestBody, &reaction.StatusCode, &reaction.ResponseSnippet, &reaction.DurationMs, &reaction.Error, &outputsJSON); err != nil {
+			return nil, 0, err
+		}
+		if outputsJSON != nil {
+			if err := json.Unmarshal(outputsJSON, &reaction.Outputs); err != nil {
+				return nil, 0, err
+			}
+		}
+		if i, ok := byID[executionID]; ok {
+			executions[i].Reactions = append(executions[i].Reactions, reaction)
+		}
+	}
+	return executions, total, reactionRows.Err()
+}
+
+func (e executionRepository) ReactionOutputs(executionID int) (map[int][]domain.InputField, error) {
+	rows, err := e.db.Query(
+		`SELECT reaction_id, outputs FROM reaction_executions
+		 WHERE execution_id = $1 AND error = '' AND outputs IS NOT NULL
+		 ORDER BY id`,
+		executionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	outputs := make(map[int][]domain.InputField)
+	for rows.Next() {
+		var reactionID int
+		var outputsJSON []byte
+		if err := rows.Scan(&reactionID, &outputsJSON); err != nil {
+			return nil, err
+		}
+		var fields []domain.InputField
+		if err := json.Unmarshal(outputsJSON, &fields); err != nil {
+			return nil, err
+		}
+		// a replayed reaction overrides its earlier outputs
+		outputs[reactionID] = fields
+	}
+	return outputs, rows.Err()
+}
+
+func (e executionRepository) DeleteOlderThan(before time.Time) (int64, error) {
+	result, err := e.db.Exec("DELETE FROM executions WHERE started_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
+)
+
+type reactionJobRepository struct {
+	db *sql.DB
+}
+
+func NewReactionJobRepository(db *sql.DB) domain.ReactionJobRepository {
+	return &reactionJobRepository{db: db}
+}
+
+func (r reactionJobRepository) Enqueue(execution domain.Execution, jobs []domain.ReactionJob) (domain.Execution, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return execution, err
+	}
+	defer tx.Rollback()
+
+	execution, err = insertExecution(tx, execution)
+	if err != nil {
+		return execution, err
+	}
+	for i, reaction := range execution.Reactions {
+		execution.Reactions[i].ID, err = insertReactionExecution(tx, execution.ID, reaction)
+		if err != nil {
+			return execution, err
+		}
+	}
+	for _, job := range jobs {
+		job.ExecutionID = execution.ID
+		if _, err := insertJob(tx, job); err != nil {
+			return execution, err
+		}
+	}
+	if err := settleExecution(tx, execution.ID); err != nil {
+		return execution, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return execution, err
+	}
+	return execution, nil
+}
+
+func insertJob(tx *sql.Tx, job domain.ReactionJob) (domain.ReactionJob, error) {
+	outputJSON, err := json.Marshal(job.OutputFields)
+	if err != nil {
+		return job, err
+	}
+	err = tx.QueryRow(
+		`INSERT INTO reaction_jobs (execution_id, area_id, reaction_id, position, chained, user_id, request_id, output_fields, max_attempts)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, next_run_at`,
+		job.ExecutionID, job.AreaID, job.ReactionID, job.Position, job.Chained, job.UserID, job.RequestID, outputJSON, job.MaxAttempts,
+	).Scan(&job.ID, &job.NextRunAt)
+	return job, err
+}
+
+func (r reactionJobRepository) Claim(staleAfter time.Duration) (*domain.ReactionJob, error) {
+	var job domain.ReactionJob
+	var outputJSON []byte
+	err := r.db.QueryRow(
+		`UPDATE reaction_jobs SET status = 'running', locked_at = NOW(), attempts = attempts + 1
+		 WHERE id = (
+		   SELECT job.id FROM reaction_jobs job
+		   WHERE ((job.status = 'pending' AND job.next_run_at <= NOW())
+		      OR (job.status = 'running' AND job.locked_at < NOW() - $1 * INTERVAL '1 millisecond'))
+		     AND NOT (job.chained AND (EXISTS (
+		       SELECT 1 FROM reaction_jobs earlier
+		       WHERE earlier.execution_id = job.execution_id AND earlier.position < job.position
+		     ) OR EXISTS (
+		       SELECT 1 FROM reaction_dead_letters earlier
+		       WHERE earlier.execution_id = job.execution_id AND earlier.position < job.position
+		     )))
+		   ORDER BY job.next_run_at
+		   FOR UPDATE SKIP LOCKED
+		   LIMIT 1
+		 )
+		 RETURNING id, execution_id, area_id, reaction_id, position, chained, user_id, request_id, output_fields, attempts, max_attempts, next_run_at, last_error`,
+		staleAfter.Milliseconds(),
+	).Scan(&job.ID, &job.ExecutionID, &job.AreaID, &job.ReactionID, &job.Position, &job.Chained, &job.UserID, &job.RequestID, &outputJSON, &job.Attempts, &job.MaxAttempts, &job.NextRunAt, &job.LastError)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(outputJSON, &job.OutputFields); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r reactionJobRepository) Complete(job domain.ReactionJob) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM reaction_jobs WHERE id = $1", job.ID); err != nil {
+		return err
+	}
+	if err := settleExecution(tx, job.ExecutionID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r reactionJobRepository) Retry(job domain.ReactionJob, delay time.Duration, lastError string) error {
+	_, err := r.db.Exec(
+		`UPDATE reaction_jobs SET status = 'pending', locked_at = NULL, last_error = $1,
+		   next_run_at = NOW() + $2 * INTERVAL '1 millisecond'
+		 WHERE id = $3`,
+		lastError, delay.Milliseconds(), job.ID,
+	)
+	return err
+}
+
+func (r reactionJobRepository) Bury(job domain.ReactionJob, lastError string, lastStatusCode int) error {
+	outputJSON, err := json.Marshal(job.OutputFields)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`INSERT INTO reaction_dead_letters (execution_id, area_id, reaction_id, position, chained, user_id, request_id, output_fields, attempts, last_error, last_status_code)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		job.ExecutionID, job.AreaID, job.ReactionID, job.Position, job.Chained, job.UserID, job.RequestID, outputJSON, job.Attempts, lastError, lastStatusCode,
+	)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM reaction_jobs WHERE id = $1", job.ID); err != nil {
+		return err
+	}
+	// the later chained jobs would fail on the missing outputs: bury them
+	// too, so they are replayed once this one is
+	_, err = tx.Exec(
+		`INSERT INTO reaction_dead_letters (execution_id, area_id, reaction_id, position, chained, user_id, request_id, output_fields, attempts, last_error)
+		 SELECT execution_id, area_id, reaction_id, position, chained, user_id, request_id, output_fields, attempts, $3
+		 FROM reaction_jobs WHERE execution_id = $1 AND chained AND position > $2`,
+		job.ExecutionID, job.Position, fmt.Sprintf("reaction %d failed", job.Position),
+	)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM reaction_jobs WHERE execution_id = $1 AND chained AND position > $2", job.ExecutionID, job.Position); err != nil {
+		return err
+	}
+	if err := settleExecution(tx, job.ExecutionID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+const deadLetterColumns = `id, execution_id, area_id, reaction_id, position, chained, user_id, request_id, output_fields, attempts, last_error, last_status_code, failed_at`
+
+func scanDeadLetter(scan func(dest ...any) error) (domain.DeadLetter, error) {
+	var letter domain.DeadLetter
+	var outputJSON []byte
+	err := scan(&letter.ID, &letter.ExecutionID, &letter.AreaID, &letter.ReactionID, &letter.Position, &letter.Chained, &letter.UserID, &letter.RequestID, &outputJSON, &letter.Attempts, &letter.LastError, &letter.LastStatusCode, &letter.FailedAt)
+	if err != nil {
+		return letter, err
+	}
+	err = json.Unmarshal(outputJSON, &letter.OutputFields)
+	return letter, err
+}
+
+// ListDeadLetters returns a page of the user dead letters, most recent first,
+// restricted to one area when areaID is not 0.
+func (r reactionJobRepository) ListDeadLetters(userID int, areaID int, limit int, offset int) ([]domain.DeadLetter, int, error) {
+	var total int
+	err := r.db.QueryRow(
+		"SELECT COUNT(*) FROM reaction_dead_letters WHERE user_id = $1 AND ($2 = 0 OR area_id = $2)",
+		userID, areaID,
+	).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(
+		`SELECT `+deadLetterColumns+` FROM reaction_dead_letters
+		 WHERE user_id = $1 AND ($2 = 0 OR area_id = $2)
+		 ORDER BY failed_at DESC, id DESC
+		 LIMIT $3 OFFSET $4`,
+		userID, areaID, limit, offset,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	letters := make([]domain.DeadLetter, 0)
+	for rows.Next() {
+		letter, err := scanDeadLetter(rows.Scan)
+		if err != nil {
+			return nil, 0, err
+		}
+		letters = append(letters, letter)
+	}
+	return letters, total, rows.Err()
+}
+
+func (r reactionJobRepository) GetDeadLetter(id int) (*domain.DeadLetter, error) {
+	letter, err := scanDeadLetter(r.db.QueryRow("SELECT "+deadLetterColumns+" FROM reaction_dead_letters WHERE id = $1", id).Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &letter, nil
+}
+
+func (r reactionJobRepository) Replay(letter domain.DeadLetter, maxAttempts int) (domain.ReactionJob, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return domain.ReactionJob{}, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM reaction_dead_letters WHERE id = $1", letter.ID)
+	if err != nil {
+		return domain.ReactionJob{}, err
+	}
+	if deleted, err := result.RowsAffected(); err != nil {
+		return domain.ReactionJob{}, err
+	} else if deleted == 0 {
+		// replayed concurrently
+		return domain.ReactionJob{}, sql.ErrNoRows
+	}
+
+	job, err := insertJob(tx, domain.ReactionJob{
+		ExecutionID:  letter.ExecutionID,
+		AreaID:       letter.AreaID,
+		ReactionID:   letter.ReactionID,
+		Position:     letter.Position,
+		Chained:      letter.Chained,
+		UserID:       letter.UserID,
+		RequestID:    letter.RequestID,
+		OutputFields: letter.OutputFields,
+		MaxAttempts:  maxAttempts,
+	})
+	if err != nil {
+		return job, err
+	}
+	_, err = tx.Exec("UPDATE executions SET status = $1, error = '' WHERE id = $2", domain.ExecutionQueued, letter.ExecutionID)
+	if err != nil {
+		return job, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return job, err
+	}
+	return job, nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReactionJobRepository_Bury_BuriesChainedJobs(t *testing.T) {
+	fake := &insertDB{}
+	repo := NewReactionJobRepository(sql.OpenDB(fake))
+
+	err := repo.Bury(domain.ReactionJob{
+		ID: 3, ExecutionID: 7, AreaID: 1, ReactionID: 10, Position: 1, Chained: true,
+		UserID: 42, RequestID: "req", Attempts: 5,
+	}, "timeout", 503)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fake.commits)
+
+	letter, ok := fake.find("INSERT INTO reaction_dead_letters", "VALUES")
+	if assert.True(t, ok) {
+		assert.Equal(t, []driver.Value{int64(7), int64(1), int64(10), int64(1), true, int64(42), "req", []byte("null"), int64(5), "timeout", int64(503)}, letter.args)
+	}
+	dependents, ok := fake.find("INSERT INTO reaction_dead_letters", "FROM reaction_jobs WHERE execution_id = $1 AND chained AND position > $2")
+	if assert.True(t, ok) {
+		assert.Equal(t, []driver.Value{int64(7), int64(1), "reaction 1 failed"}, dependents.args)
+	}
+	deleted, ok := fake.find("DELETE FROM reaction_jobs WHERE execution_id = $1 AND chained AND position > $2")
+	if assert.True(t, ok) {
+		assert.Equal(t, []driver.Value{int64(7), int64(1)}, deleted.args)
+	}
+}
+
+func TestReactionJobRepository_Replay_KeepsPosition(t *testing.T) {
+	fake := &insertDB{}
+	repo := NewReactionJobRepository(sql.OpenDB(fake))
+
+	job, err := repo.Replay(domain.DeadLetter{
+		ID: 9, ExecutionID: 7, AreaID: 1, ReactionID: 11, Position: 2, Chained: true,
+		UserID: 42, RequestID: "req", Attempts: 5,
+	}, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fake.commits)
+	assert.Equal(t, []string{"reaction_jobs"}, fake.inserts)
+	assert.Equal(t, 1, job.ID)
+	assert.Equal(t, 2, job.Position)
+	assert.True(t, job.Chained)
+
+	inserted, ok := fake.find("INSERT INTO reaction_jobs")
+	if assert.True(t, ok) {
+		assert.Equal(t, []driver.Value{int64(7), int64(1), int64(11), int64(2), true, int64(42), "req", []byte("null"), int64(3)}, inserted.args)
+	}
+}
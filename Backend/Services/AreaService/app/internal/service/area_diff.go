@@ -0,0 +1,111 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
+)
+
+var ErrUnknownAreaItem = errors.New("action or reaction does not belong to the area")
+
+// DiffArea compares an edited area with the stored one. Actions and reactions
+// are matched by id: an id of 0 is a new row, a stored row missing from the
+// edit is deleted. Rows left untouched by the edit are not part of the diff.
+func DiffArea(current, updated domain.Area) (domain.AreaDiff, error) {
+	var diff domain.AreaDiff
+
+	storedActions := make(map[int]domain.AreaAction, len(current.Actions))
+	for _, action := range current.Actions {
+		storedActions[action.ID] = action
+	}
+	seen := make(map[int]bool)
+	for _, action := range updated.Actions {
+		if action.ID == 0 {
+			diff.CreatedActions = append(diff.CreatedActions, action)
+			continue
+		}
+		stored, ok := storedActions[action.ID]
+		if !ok || seen[action.ID] {
+			return domain.AreaDiff{}, fmt.Errorf("%w: action %d", ErrUnknownAreaItem, action.ID)
+		}
+		seen[action.ID] = true
+		if actionNeedsRegistration(stored, action) {
+			diff.UpdatedActions = append(diff.UpdatedActions, action)
+		}
+	}
+	for _, action := range current.Actions {
+		if !seen[action.ID] {
+			diff.DeletedActions = append(diff.DeletedActions, action)
+		}
+	}
+
+	storedReactions := make(map[int]domain.AreaReaction, len(current.Reactions))
+	for _, reaction := range current.Reactions {
+		storedReactions[reaction.ID] = reaction
+	}
+	seen = make(map[int]bool)
+	for _, reaction := range updated.Reactions {
+		if reaction.ID == 0 {
+			diff.CreatedReactions = append(diff.CreatedReactions, reaction)
+			continue
+		}
+		stored, ok := storedReactions[reaction.ID]
+		if !ok || seen[reaction.ID] {
+			return domain.AreaDiff{}, fmt.Errorf("%w: reaction %d", ErrUnknownAreaItem, reaction.ID)
+		}
+		seen[reaction.ID] = true
+		if stored.Provider != reaction.Provider || stored.Service != reaction.Service ||
+			stored.Title != reaction.Title || !sameInputs(stored.Input, reaction.Input) ||
+			!sameCondition(stored.Condition, reaction.Condition) {
+			diff.UpdatedReactions = append(diff.UpdatedReactions, reaction)
+		}
+	}
+	for _, reaction := range current.Reactions {
+		if !seen[reaction.ID] {
+			diff.DeletedReactions = append(diff.DeletedReactions, reaction)
+		}
+	}
+
+	return diff, nil
+}
+
+// actionNeedsRegistration reports whether the trigger service of the action
+// has to forget the stored action and register the edited one.
+func actionNeedsRegistration(stored, edited domain.AreaAction) bool {
+	return stored.Type != edited.Type ||
+		stored.Provider != edited.Provider ||
+		stored.Service != edited.Service ||
+		stored.Title != edited.Title ||
+		!sameInputs(stored.Input, edited.Input)
+}
+
+// sameInputs compares input fields by name, regardless of their order.
+func sameInputs(a, b []domain.InputField) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	values := make(map[string]string, len(a))
+	for _, field := range a {
+		values[field.Name] = field.Value
+	}
+	for _, field := range b {
+		value, ok := values[field.Name]
+		if !ok || value != field.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// sameCondition compares conditions by their JSON form, as they are stored.
+func sameCondition(a, b *domain.ReactionCondition) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	left, errLeft := json.Marshal(a)
+	right, errRight := json.Marshal(b)
+	return errLeft == nil && errRight == nil && bytes.Equal(left, right)
+}
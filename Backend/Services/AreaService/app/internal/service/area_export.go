@@ -0,0 +1,126 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
+)
+
+var ErrInvalidAreaExport = errors.New("invalid area export")
+
+// NewAreaExport wraps exported areas in a document of the current version.
+func NewAreaExport(areas []domain.ExportedArea) domain.AreaExport {
+	return domain.AreaExport{
+		Format:     domain.AreaExportFormat,
+		Version:    domain.AreaExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Areas:      areas,
+	}
+}
+
+// ExportArea converts an area to its exported form. config is the area
+// configuration, in the order of its actions and reactions; the inputs of its
+// secret fields are emptied.
+func ExportArea(area domain.Area, config domain.AreaConfig) domain.ExportedArea {
+	exported := domain.ExportedArea{
+		Name:      area.Name,
+		Active:    area.Active,
+		Actions:   make([]domain.ExportedAction, 0, len(area.Actions)),
+		Reactions: make([]domain.ExportedReaction, 0, len(area.Reactions)),
+	}
+	for i, action := range area.Actions {
+		var fields []domain.FieldConfig
+		if i < len(config.Actions) {
+			fields = config.Actions[i].Fields
+		}
+		input, secrets := exportInput(action.Input, fields, fmt.Sprintf("actions.%d", i))
+		exported.Secrets = append(exported.Secrets, secrets...)
+		exported.Actions = append(exported.Actions, domain.ExportedAction{
+			Provider: action.Provider,
+			Service:  action.Service,
+			Title:    action.Title,
+			Type:     action.Type,
+			Input:    input,
+		})
+	}
+	for i, reaction := range area.Reactions {
+		var fields []domain.FieldConfig
+		if i < len(config.Reactions) {
+			fields = config.Reactions[i].Fields
+		}
+		input, secrets := exportInput(reaction.Input, fields, fmt.Sprintf("reactions.%d", i))
+		exported.Secrets = append(exported.Secrets, secrets...)
+		exported.Reactions = append(exported.Reactions, domain.ExportedReaction{
+			Provider:  reaction.Provider,
+			Service:   reaction.Service,
+			Title:     reaction.Title,
+			Input:     input,
+			Condition: reaction.Condition,
+		})
+	}
+	return exported
+}
+
+func exportInput(input []domain.InputField, fields []domain.FieldConfig, prefix string) ([]domain.InputField, []string) {
+	secret := make(map[string]bool)
+	for _, field := range fields {
+		if field.Secret {
+			secret[field.Name] = true
+		}
+	}
+	exported := make([]domain.InputField, 0, len(input))
+	var secrets []string
+	for _, field := range input {
+		if secret[field.Name] {
+			secrets = append(secrets, prefix+"."+field.Name)
+			field.Value = ""
+		}
+		exported = append(exported, field)
+	}
+	return exported, secrets
+}
+
+// CheckAreaExport checks the format and version of a document to import.
+func CheckAreaExport(document domain.AreaExport) error {
+	if document.Format != domain.AreaExportFormat {
+		return fmt.Errorf("%w: format %q is not %s", ErrInvalidAreaExport, document.Format, domain.AreaExportFormat)
+	}
+	if document.Version < 1 || document.Version > domain.AreaExportVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidAreaExport, document.Version)
+	}
+	if len(document.Areas) == 0 {
+		return fmt.Errorf("%w: no area to import", ErrInvalidAreaExport)
+	}
+	return nil
+}
+
+// ImportArea turns an exported area into a new, inactive area of the user.
+func ImportArea(exported domain.ExportedArea, userID int) domain.Area {
+	area := domain.Area{
+		Name:      exported.Name,
+		UserID:    userID,
+		Actions:   make([]domain.AreaAction, 0, len(exported.Actions)),
+		Reactions: make([]domain.AreaReaction, 0, len(exported.Reactions)),
+	}
+	for _, action := range exported.Actions {
+		area.Actions = append(area.Actions, domain.AreaAction{
+			Provider: action.Provider,
+			Service:  action.Service,
+			Title:    action.Title,
+			Type:     action.Type,
+			Input:    action.Input,
+		})
+	}
+	for _, reaction := range exported.Reactions {
+		area.Reactions = append(area.Reactions, domain.AreaReaction{
+			Provider:  reaction.Provider,
+			Service:   reaction.Service,
+			Title:     reaction.Title,
+			Input:     reaction.Input,
+			Condition: reaction.Condition,
+		})
+	}
+	return area
+}
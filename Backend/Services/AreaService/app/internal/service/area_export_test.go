@@ -0,0 +1,109 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func exportTestArea() (domain.Area, domain.AreaConfig) {
+	area := domain.Area{
+		ID:     3,
+		Name:   "Notion to Discord",
+		Active: true,
+		UserID: 42,
+		Actions: []domain.AreaAction{{
+			ID: 5, Active: true, Service: "notion", Title: "new_page", Type: "polling",
+			Input: []domain.InputField{{Name: "database_id", Value: "db-1"}, {Name: "token", Value: "secret_abc"}},
+		}},
+		Reactions: []domain.AreaReaction{{
+			ID: 7, Service: "discord", Title: "send_webhook_message",
+			Input: []domain.InputField{
+				{Name: "webhook_url", Value: "https://discord.com/api/webhooks/1/xyz"},
+				{Name: "content", Value: "{{title | upper}}"},
+			},
+			Condition: &domain.ReactionCondition{Field: "title", Operator: "exists"},
+		}},
+	}
+	config := domain.AreaConfig{
+		Actions: []domain.ActionConfig{{Title: "new_page", Fields: []domain.FieldConfig{
+			{Name: "database_id", Required: true},
+			{Name: "token", Required: true, Secret: true},
+		}}},
+		Reactions: []domain.ReactionConfig{{Title: "send_webhook_message", Fields: []domain.FieldConfig{
+			{Name: "webhook_url", Required: true, Secret: true},
+			{Name: "content", Required: true},
+		}}},
+	}
+	return area, config
+}
+
+func TestExportArea_LeavesSecretsOut(t *testing.T) {
+	area, config := exportTestArea()
+
+	exported := ExportArea(area, config)
+
+	assert.Equal(t, "Notion to Discord", exported.Name)
+	assert.True(t, exported.Active)
+	assert.Equal(t, []domain.InputField{{Name: "database_id", Value: "db-1"}, {Name: "token", Value: ""}}, exported.Actions[0].Input)
+	assert.Equal(t, []domain.InputField{
+		{Name: "webhook_url", Value: ""},
+		{Name: "content", Value: "{{title | upper}}"},
+	}, exported.Reactions[0].Input)
+	assert.Equal(t, area.Reactions[0].Condition, exported.Reactions[0].Condition)
+	assert.Equal(t, []string{"actions.0.token", "reactions.0.webhook_url"}, exported.Secrets)
+
+	encoded, err := json.Marshal(NewAreaExport([]domain.ExportedArea{exported}))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(encoded), "secret_abc")
+	assert.NotContains(t, string(encoded), "webhooks/1/xyz")
+	assert.NotContains(t, string(encoded), `"user_id"`)
+	assert.NotContains(t, string(encoded), `"id"`)
+}
+
+func TestImportArea_CreatesInactiveArea(t *testing.T) {
+	area, config := exportTestArea()
+	exported := ExportArea(area, config)
+
+	imported := ImportArea(exported, 99)
+
+	assert.Zero(t, imported.ID)
+	assert.False(t, imported.Active)
+	assert.Equal(t, 99, imported.UserID)
+	assert.Equal(t, "Notion to Discord", imported.Name)
+	assert.Len(t, imported.Actions, 1)
+	assert.Zero(t, imported.Actions[0].ID)
+	assert.False(t, imported.Actions[0].Active)
+	assert.Equal(t, "polling", imported.Actions[0].Type)
+	assert.Equal(t, "send_webhook_message", imported.Reactions[0].Title)
+	assert.Equal(t, area.Reactions[0].Condition, imported.Reactions[0].Condition)
+}
+
+func TestCheckAreaExport(t *testing.T) {
+	areas := []domain.ExportedArea{{Name: "area"}}
+
+	testCases := []struct {
+		name     string
+		document domain.AreaExport
+		valid    bool
+	}{
+		{"current version", domain.AreaExport{Format: domain.AreaExportFormat, Version: domain.AreaExportVersion, Areas: areas}, true},
+		{"unknown format", domain.AreaExport{Format: "zapier", Version: 1, Areas: areas}, false},
+		{"missing version", domain.AreaExport{Format: domain.AreaExportFormat, Areas: areas}, false},
+		{"newer version", domain.AreaExport{Format: domain.AreaExportFormat, Version: domain.AreaExportVersion + 1, Areas: areas}, false},
+		{"no area", domain.AreaExport{Format: domain.AreaExportFormat, Version: domain.AreaExportVersion}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckAreaExport(tc.document)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidAreaExport)
+			}
+		})
+	}
+}
@@ -8,13 +8,24 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"regexp"
 	"strconv"
 	"strings" // Ajout de strings
+	"time"
 
 	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
 )
 
+const (
+	maxRecordedBody    = 4096
+	maxResponseSnippet = 1024
+	maxPreviewBody     = 64 << 10
+	maskedSecret       = "********"
+)
+
+// reactionClient bounds every reaction request so a hanging endpoint cannot
+// hold a worker forever.
+var reactionClient = &http.Client{Timeout: 30 * time.Second}
+
 type AreaService struct {
 	areaRepo       domain.AreaRepository
 	internalSecret string
@@ -65,26 +76,100 @@ func (s *AreaService) CreateCalendarEvent(authToken string, event domain.Event)
 	return event, nil
 }
 
-func (s *AreaService) LaunchReactions(userToken string, fieldValues map[string]string, reaction domain.ReactionConfig) error {
-	envPlaceholderRegexp := regexp.MustCompile(`\{\{\s*env\.([A-Za-z0-9_]+)\s*\}\}`)
-	replacePlaceholders := func(input string) string {
-		result := input
-		for key, value := range fieldValues {
-			placeholder := "{{" + key + "}}"
-			result = strings.ReplaceAll(result, placeholder, value)
+// LaunchReaction calls the reaction endpoint and returns what was sent and
+// received, for the execution history. Values read from the environment are
+// masked in the returned request.
+func (s *AreaService) LaunchReaction(requestID string, userToken string, fieldValues map[string]string, reaction domain.ReactionConfig) (domain.ReactionExecution, error) {
+	requestID = RequestIDOrNew(requestID)
+
+	var result domain.ReactionExecution
+	fail := func(err error) (domain.ReactionExecution, error) {
+		result.Error = err.Error()
+		return result, err
+	}
+
+	rendered, err := s.renderReaction(requestID, userToken, fieldValues, reaction)
+	result.Method = rendered.method
+	result.URL = rendered.mask(rendered.url)
+	result.RequestBody = recordable(rendered.mask(rendered.body), maxRecordedBody)
+	if err != nil {
+		return fail(err)
+	}
+	req := rendered.request
+	method, url, mask := rendered.method, rendered.url, rendered.mask
+	log.Printf("launching reaction %s %s (request_id=%s)", method, url, requestID)
+	start := time.Now()
+	resp, err := reactionClient.Do(req)
+	result.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		log.Printf("reaction %s %s failed (request_id=%s): %v", method, url, requestID, err)
+		return fail(fmt.Errorf("failed to call reaction endpoint: %w", err))
+	}
+	defer resp.Body.Close()
+	log.Printf("reaction %s %s returned status %d (request_id=%s)", method, url, resp.StatusCode, requestID)
+
+	result.StatusCode = resp.StatusCode
+	limit := int64(maxResponseSnippet + 1)
+	if len(reaction.OutputMappings) > 0 {
+		limit = maxMappedResponse
+	}
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, limit))
+	result.ResponseSnippet = recordable(mask(string(respBody)), maxResponseSnippet)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fail(fmt.Errorf("reaction request returned status %d: %s", resp.StatusCode, strings.TrimSpace(result.ResponseSnippet)))
+	}
+	if len(reaction.OutputMappings) > 0 {
+		outputs, err := extractOutputs(respBody, reaction.OutputMappings)
+		if err != nil {
+			return fail(fmt.Errorf("failed to map reaction response: %w", err))
 		}
-		result = envPlaceholderRegexp.ReplaceAllStringFunc(result, func(match string) string {
-			parts := envPlaceholderRegexp.FindStringSubmatch(match)
-			if len(parts) != 2 {
-				return match
+		result.Outputs = outputs
+	}
+	return result, nil
+}
+
+// renderedReaction is a reaction request ready to be sent. mask hides the
+// values read from the environment.
+type renderedReaction struct {
+	request *http.Request
+	method  string
+	url     string
+	body    string
+	mask    func(string) string
+}
+
+func (s *AreaService) renderReaction(requestID string, userToken string, fieldValues map[string]string, reaction domain.ReactionConfig) (renderedReaction, error) {
+	envValues := make(map[string]string)
+	rendered := renderedReaction{}
+	rendered.mask = func(input string) string {
+		for value, placeholder := range envValues {
+			input = strings.ReplaceAll(input, value, placeholder)
+		}
+		return input
+	}
+
+	// The field values may hold env placeholders, so these are rendered in a
+	// second pass, which also applies the default filters.
+	replacePlaceholders := func(input string) (string, error) {
+		rendered, err := RenderTemplate(input, func(key string) (string, bool) {
+			value, ok := fieldValues[key]
+			return value, ok
+		}, false)
+		if err != nil {
+			return "", err
+		}
+		return RenderTemplate(rendered, func(key string) (string, bool) {
+			name, ok := strings.CutPrefix(key, "env.")
+			if !ok {
+				return "", false
 			}
-			envValue := os.Getenv(parts[1])
+			envValue := os.Getenv(name)
 			if envValue == "" {
-				return match
+				return "", false
 			}
-			return envValue
-		})
-		return result
+			envValues[envValue] = "{{env." + name + "}}"
+			return envValue, true
+		}, true)
 	}
 
 	setNestedValue := func(target map[string]any, path string, value any) {
@@ -166,7 +251,11 @@ func (s *AreaService) LaunchReactions(userToken string, fieldValues map[string]s
 
 				var strVal string
 				if err := json.Unmarshal(rawItem, &strVal); err == nil {
-					result = append(result, replacePlaceholders(strVal))
+					item, err := replacePlaceholders(strVal)
+					if err != nil {
+						return nil, err
+					}
+					result = append(result, item)
 					continue
 				}
 
@@ -182,7 +271,10 @@ func (s *AreaService) LaunchReactions(userToken string, fieldValues map[string]s
 
 		default:
 			valStr := strings.Trim(string(field.Value), `"`)
-			finalVal := replacePlaceholders(valStr)
+			finalVal, err := replacePlaceholders(valStr)
+			if err != nil {
+				return nil, err
+			}
 
 			switch strings.ToLower(field.Type) {
 			case "boolean":
@@ -199,49 +291,54 @@ func (s *AreaService) LaunchReactions(userToken string, fieldValues map[string]s
 	}
 
 	var bodyReader io.Reader
+	bodyText := ""
 	contentType := ""
 	if strings.EqualFold(reaction.BodyType, "binary") {
 		if len(reaction.BodyStruct) == 1 {
 			val, err := buildValue(reaction.BodyStruct[0])
 			if err != nil {
-				return err
+				return rendered, err
 			}
-			bodyReader = strings.NewReader(fmt.Sprint(val))
+			bodyText = fmt.Sprint(val)
+			bodyReader = strings.NewReader(bodyText)
 		} else if len(reaction.BodyStruct) > 1 {
 			payload, err := buildPayload(reaction.BodyStruct)
 			if err != nil {
-				return err
+				return rendered, err
 			}
-			bodyReader = strings.NewReader(fmt.Sprint(payload))
+			bodyText = fmt.Sprint(payload)
+			bodyReader = strings.NewReader(bodyText)
 		}
 	} else {
 		payload, err := buildPayload(reaction.BodyStruct)
 		if err != nil {
-			return err
+			return rendered, err
 		}
 		body, err := json.Marshal(payload)
 		if err != nil {
-			return fmt.Errorf("failed to marshal event payload: %w", err)
+			return rendered, fmt.Errorf("failed to marshal event payload: %w", err)
 		}
+		bodyText = string(body)
 		bodyReader = bytes.NewReader(body)
 		contentType = "application/json"
 	}
-	url := reaction.Url
-
-	for key, value := range fieldValues {
-		url = strings.ReplaceAll(url, "{{"+key+"}}", value)
+	url, err := replacePlaceholders(reaction.Url)
+	if err != nil {
+		return rendered, err
 	}
-	url = replacePlaceholders(url)
-	log.Println(url)
 
 	method := reaction.Method
 	if method == "" {
 		method = http.MethodPost
 	}
 
+	rendered.method = method
+	rendered.url = url
+	rendered.body = bodyText
+
 	req, err := http.NewRequest(method, url, bodyReader)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return rendered, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	if strings.TrimSpace(userToken) != "" {
@@ -254,22 +351,61 @@ func (s *AreaService) LaunchReactions(userToken string, fieldValues map[string]s
 		req.Header.Set("X-Internal-Secret", s.internalSecret)
 	}
 	for key, value := range reaction.Headers {
-		renderedValue := replacePlaceholders(value)
+		renderedValue, err := replacePlaceholders(value)
+		if err != nil {
+			return rendered, err
+		}
 		req.Header.Set(key, renderedValue)
 	}
 	if contentType != "" && req.Header.Get("Content-Type") == "" {
 		req.Header.Set("Content-Type", contentType)
 	}
-	resp, err := http.DefaultClient.Do(req)
+	req.Header.Set(RequestIDHeader, requestID)
+	rendered.request = req
+	return rendered, nil
+}
+
+// PreviewReaction renders the reaction request without sending it, for a
+// test of an area. The user token, the internal secret and the values read
+// from the environment are masked.
+func (s *AreaService) PreviewReaction(userToken string, fieldValues map[string]string, reaction domain.ReactionConfig) (domain.ReactionRequest, error) {
+	rendered, err := s.renderReaction(RequestIDOrNew(""), userToken, fieldValues, reaction)
 	if err != nil {
-		return fmt.Errorf("failed to call reaction endpoint: %w", err)
+		return domain.ReactionRequest{}, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("reaction request returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+
+	headers := make(map[string]string, len(rendered.request.Header))
+	for key, values := range rendered.request.Header {
+		value := strings.Join(values, ", ")
+		switch key {
+		case "Authorization", "X-Internal-Secret":
+			value = maskedSecret
+		default:
+			value = rendered.mask(value)
+		}
+		headers[key] = value
 	}
-	return nil
+	return domain.ReactionRequest{
+		Method:  rendered.method,
+		URL:     rendered.mask(rendered.url),
+		Headers: headers,
+		Body:    recordable(rendered.mask(rendered.body), maxPreviewBody),
+	}, nil
+}
+
+// recordable cuts s to max bytes and drops what a TEXT column rejects
+// (invalid UTF-8, NUL bytes), so binary payloads can be recorded too.
+func recordable(s string, max int) string {
+	truncated := false
+	if len(s) > max {
+		s = s[:max]
+		truncated = true
+	}
+	s = strings.ReplaceAll(strings.ToValidUTF8(s, ""), "\x00", "")
+	if truncated {
+		s += "..."
+	}
+	return s
 }
 
 func (s *AreaService) GetUserAreas(userId int) ([]domain.Area, error) {
@@ -280,6 +416,11 @@ func (s *AreaService) SaveArea(area domain.Area) (domain.Area, error) {
 	return s.areaRepo.SaveArea(area)
 }
 
+// SaveAreas saves all the areas or none of them.
+func (s *AreaService) SaveAreas(areas []domain.Area) ([]domain.Area, error) {
+	return s.areaRepo.SaveAreas(areas)
+}
+
 func (s *AreaService) GetAreaFromAction(actionId int) (domain.Area, error) {
 	return s.areaRepo.GetAreaFromAction(actionId)
 }
@@ -292,6 +433,31 @@ func (s *AreaService) GetArea(areaID int) (domain.Area, error) {
 	return s.areaRepo.GetArea(areaID)
 }
 
+// UpdateArea applies the diff of an edited area and returns the stored result.
+// The created actions of the returned diff carry their new ids.
+func (s *AreaService) UpdateArea(current, updated domain.Area) (domain.Area, domain.AreaDiff, error) {
+	diff, err := DiffArea(current, updated)
+	if err != nil {
+		return current, domain.AreaDiff{}, err
+	}
+	area, err := s.areaRepo.UpdateArea(updated, diff)
+	if err != nil {
+		return current, domain.AreaDiff{}, err
+	}
+
+	stored := make(map[int]bool, len(current.Actions))
+	for _, action := range current.Actions {
+		stored[action.ID] = true
+	}
+	diff.CreatedActions = nil
+	for _, action := range area.Actions {
+		if !stored[action.ID] {
+			diff.CreatedActions = append(diff.CreatedActions, action)
+		}
+	}
+	return area, diff, nil
+}
+
 func (s *AreaService) ToggleArea(areaID int, isActive bool) error {
 	return s.areaRepo.ToggleArea(areaID, isActive)
 }
@@ -3,6 +3,10 @@ package service
 import (
 	"encoding/json"
 	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
@@ -28,6 +32,14 @@ func (m *MockAreaRepository) SaveArea(area domain.Area) (domain.Area, error) {
 	return args.Get(0).(domain.Area), args.Error(1)
 }
 
+func (m *MockAreaRepository) SaveAreas(areas []domain.Area) ([]domain.Area, error) {
+	args := m.Called(areas)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Area), args.Error(1)
+}
+
 func (m *MockAreaRepository) GetAreaFromAction(actionID int) (domain.Area, error) {
 	args := m.Called(actionID)
 	return args.Get(0).(domain.Area), args.Error(1)
@@ -54,6 +66,11 @@ func (m *MockAreaRepository) GetArea(areaID int) (domain.Area, error) {
 	return args.Get(0).(domain.Area), args.Error(1)
 }
 
+func (m *MockAreaRepository) UpdateArea(area domain.Area, diff domain.AreaDiff) (domain.Area, error) {
+	args := m.Called(area, diff)
+	return args.Get(0).(domain.Area), args.Error(1)
+}
+
 func (m *MockAreaRepository) ToggleArea(areaID int, isActive bool) error {
 	args := m.Called(areaID, isActive)
 	return args.Error(0)
@@ -231,6 +248,122 @@ func TestAreaService_GetArea_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestDiffArea_OnlyChangedRows(t *testing.T) {
+	current := domain.Area{
+		ID: 1,
+		Actions: []domain.AreaAction{
+			{ID: 10, Type: "webhook", Title: "push", Input: []domain.InputField{{Name: "repo", Value: "a"}, {Name: "branch", Value: "main"}}},
+			{ID: 11, Type: "polling", Title: "mail", Input: []domain.InputField{{Name: "from", Value: "x"}}},
+			{ID: 12, Type: "polling", Title: "rss"},
+		},
+		Reactions: []domain.AreaReaction{
+			{ID: 20, Title: "message", Input: []domain.InputField{{Name: "text", Value: "hi"}}},
+			{ID: 21, Title: "email"},
+		},
+	}
+	updated := domain.Area{
+		ID: 1,
+		Actions: []domain.AreaAction{
+			// same inputs in another order
+			{ID: 10, Type: "webhook", Title: "push", Input: []domain.InputField{{Name: "branch", Value: "main"}, {Name: "repo", Value: "a"}}},
+			{ID: 11, Type: "polling", Title: "mail", Input: []domain.InputField{{Name: "from", Value: "y"}}},
+			{Type: "cron", Title: "timer"},
+		},
+		Reactions: []domain.AreaReaction{
+			{ID: 20, Title: "message", Input: []domain.InputField{{Name: "text", Value: "hello"}}},
+			{ID: 21, Title: "email"},
+		},
+	}
+
+	diff, err := DiffArea(current, updated)
+
+	assert.NoError(t, err)
+	assert.Len(t, diff.CreatedActions, 1)
+	assert.Equal(t, "timer", diff.CreatedActions[0].Title)
+	assert.Len(t, diff.UpdatedActions, 1)
+	assert.Equal(t, 11, diff.UpdatedActions[0].ID)
+	assert.Len(t, diff.DeletedActions, 1)
+	assert.Equal(t, 12, diff.DeletedActions[0].ID)
+	assert.Empty(t, diff.CreatedReactions)
+	assert.Len(t, diff.UpdatedReactions, 1)
+	assert.Equal(t, 20, diff.UpdatedReactions[0].ID)
+	assert.Empty(t, diff.DeletedReactions)
+}
+
+func TestDiffArea_ConditionChange(t *testing.T) {
+	condition := &domain.ReactionCondition{Field: "title", Operator: "contains", Value: "release"}
+	current := domain.Area{ID: 1, Reactions: []domain.AreaReaction{
+		{ID: 20, Title: "message", Condition: condition},
+		{ID: 21, Title: "email"},
+		{ID: 22, Title: "tweet", Condition: condition},
+	}}
+	updated := domain.Area{ID: 1, Reactions: []domain.AreaReaction{
+		{ID: 20, Title: "message", Condition: &domain.ReactionCondition{Field: "title", Operator: "contains", Value: "release"}},
+		{ID: 21, Title: "email", Condition: condition},
+		{ID: 22, Title: "tweet"},
+	}}
+
+	diff, err := DiffArea(current, updated)
+
+	assert.NoError(t, err)
+	assert.Len(t, diff.UpdatedReactions, 2)
+	assert.Equal(t, 21, diff.UpdatedReactions[0].ID)
+	assert.Equal(t, 22, diff.UpdatedReactions[1].ID)
+}
+
+func TestDiffArea_UnknownID(t *testing.T) {
+	current := domain.Area{ID: 1, Actions: []domain.AreaAction{{ID: 10}}}
+
+	testCases := []struct {
+		name    string
+		updated domain.Area
+	}{
+		{"action of another area", domain.Area{ID: 1, Actions: []domain.AreaAction{{ID: 99}}}},
+		{"duplicated action", domain.Area{ID: 1, Actions: []domain.AreaAction{{ID: 10}, {ID: 10}}}},
+		{"reaction of another area", domain.Area{ID: 1, Actions: []domain.AreaAction{{ID: 10}}, Reactions: []domain.AreaReaction{{ID: 5}}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := DiffArea(current, tc.updated)
+
+			assert.ErrorIs(t, err, ErrUnknownAreaItem)
+		})
+	}
+}
+
+func TestAreaService_UpdateArea_ReturnsCreatedActionIDs(t *testing.T) {
+	mockRepo := new(MockAreaRepository)
+	svc := NewAreaService(mockRepo, "test-secret")
+
+	current := domain.Area{ID: 1, Name: "Old", Actions: []domain.AreaAction{{ID: 10, Type: "webhook"}}}
+	updated := domain.Area{ID: 1, Name: "New", Actions: []domain.AreaAction{{ID: 10, Type: "webhook"}, {Type: "polling"}}}
+	stored := domain.Area{ID: 1, Name: "New", Actions: []domain.AreaAction{{ID: 10, Type: "webhook"}, {ID: 11, Type: "polling"}}}
+
+	mockRepo.On("UpdateArea", updated, domain.AreaDiff{CreatedActions: []domain.AreaAction{{Type: "polling"}}}).Return(stored, nil)
+
+	area, diff, err := svc.UpdateArea(current, updated)
+
+	assert.NoError(t, err)
+	assert.Equal(t, stored, area)
+	assert.Equal(t, []domain.AreaAction{{ID: 11, Type: "polling"}}, diff.CreatedActions)
+	assert.Empty(t, diff.UpdatedActions)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAreaService_UpdateArea_UnknownAction(t *testing.T) {
+	mockRepo := new(MockAreaRepository)
+	svc := NewAreaService(mockRepo, "test-secret")
+
+	current := domain.Area{ID: 1, Actions: []domain.AreaAction{{ID: 10}}}
+	updated := domain.Area{ID: 1, Actions: []domain.AreaAction{{ID: 42}}}
+
+	_, _, err := svc.UpdateArea(current, updated)
+
+	assert.ErrorIs(t, err, ErrUnknownAreaItem)
+	mockRepo.AssertNotCalled(t, "UpdateArea", mock.Anything, mock.Anything)
+}
+
 func TestAreaService_ToggleArea_Success(t *testing.T) {
 	mockRepo := new(MockAreaRepository)
 	svc := NewAreaService(mockRepo, "test-secret")
@@ -320,7 +453,7 @@ func TestAreaService_DeactivateAreasByProvider_NoAreasAffected(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
-func TestAreaService_LaunchReactions_SimplePayload(t *testing.T) {
+func TestAreaService_LaunchReaction_SimplePayload(t *testing.T) {
 	mockRepo := new(MockAreaRepository)
 	svc := NewAreaService(mockRepo, "test-secret")
 
@@ -343,13 +476,13 @@ func TestAreaService_LaunchReactions_SimplePayload(t *testing.T) {
 
 	// Note: This test will fail because it makes a real HTTP request
 	// In a real implementation, we would mock the HTTP client
-	err := svc.LaunchReactions("test-token", fieldValues, reaction)
+	_, err := svc.LaunchReaction("test-request-id", "test-token", fieldValues, reaction)
 
 	// Since we can't make the actual HTTP call succeed, we expect an error
 	assert.Error(t, err)
 }
 
-func TestAreaService_LaunchReactions_URLWithPlaceholder(t *testing.T) {
+func TestAreaService_LaunchReaction_URLWithPlaceholder(t *testing.T) {
 	mockRepo := new(MockAreaRepository)
 	svc := NewAreaService(mockRepo, "test-secret")
 
@@ -364,7 +497,128 @@ func TestAreaService_LaunchReactions_URLWithPlaceholder(t *testing.T) {
 	}
 
 	// This will fail due to actual HTTP call
-	err := svc.LaunchReactions("test-token", fieldValues, reaction)
+	_, err := svc.LaunchReaction("test-request-id", "test-token", fieldValues, reaction)
+
+	assert.Error(t, err)
+}
+
+func TestAreaService_LaunchReaction_RecordsMaskedRequest(t *testing.T) {
+	t.Setenv("REACTION_API_KEY", "s3cr3t-key")
+
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = r.URL.RawQuery + " " + string(body)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(strings.Repeat("x", maxResponseSnippet*2)))
+	}))
+	defer server.Close()
+
+	svc := NewAreaService(new(MockAreaRepository), "test-secret")
+	reaction := domain.ReactionConfig{
+		Url:    server.URL + "/send?key={{env.REACTION_API_KEY}}",
+		Method: "POST",
+		BodyStruct: []domain.BodyField{
+			{Path: "content", Type: "string", Value: json.RawMessage(`"{{message}}"`)},
+		},
+	}
+
+	result, err := svc.LaunchReaction("test-request-id", "", map[string]string{"message": "hello"}, reaction)
+
+	assert.Error(t, err)
+	assert.Contains(t, received, "key=s3cr3t-key")
+	assert.Equal(t, "POST", result.Method)
+	assert.Equal(t, server.URL+"/send?key={{env.REACTION_API_KEY}}", result.URL)
+	assert.JSONEq(t, `{"content":"hello"}`, result.RequestBody)
+	assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+	assert.Len(t, result.ResponseSnippet, maxResponseSnippet+len("..."))
+	assert.Equal(t, err.Error(), result.Error)
+}
+
+func TestAreaService_PreviewReaction_MasksSecrets(t *testing.T) {
+	t.Setenv("REACTION_API_KEY", "s3cr3t-key")
+
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	svc := NewAreaService(new(MockAreaRepository), "test-secret")
+	reaction := domain.ReactionConfig{
+		Url:     server.URL + "/send?key={{env.REACTION_API_KEY}}",
+		Method:  "POST",
+		Headers: map[string]string{"X-Api-Key": "{{env.REACTION_API_KEY}}"},
+		BodyStruct: []domain.BodyField{
+			{Path: "content", Type: "string", Value: json.RawMessage(`"{{message | upper}}"`)},
+		},
+	}
+
+	request, err := svc.PreviewReaction("user-token", map[string]string{"message": "hello"}, reaction)
+
+	assert.NoError(t, err)
+	assert.False(t, called)
+	assert.Equal(t, "POST", request.Method)
+	assert.Equal(t, server.URL+"/send?key={{env.REACTION_API_KEY}}", request.URL)
+	assert.JSONEq(t, `{"content":"HELLO"}`, request.Body)
+	assert.Equal(t, maskedSecret, request.Headers["Authorization"])
+	assert.Equal(t, maskedSecret, request.Headers["X-Internal-Secret"])
+	assert.Equal(t, "{{env.REACTION_API_KEY}}", request.Headers["X-Api-Key"])
+	assert.Equal(t, "application/json", request.Headers["Content-Type"])
+}
+
+func TestAreaService_LaunchReaction_MapsOutputs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"evt-1","htmlLink":"https://calendar/evt-1","attendees":[{"email":"a@b.c"}]}`))
+	}))
+	defer server.Close()
+
+	svc := NewAreaService(new(MockAreaRepository), "test-secret")
+	reaction := domain.ReactionConfig{
+		Url:    server.URL,
+		Method: "POST",
+		OutputMappings: []domain.ReactionOutputMapping{
+			{FieldKey: "event_id", JSONPath: "$.id"},
+			{FieldKey: "event_link", JSONPath: "$.htmlLink"},
+			{FieldKey: "first_attendee", JSONPath: "$.attendees[0].email"},
+			{FieldKey: "location", JSONPath: "$.location", Optional: true},
+		},
+	}
+
+	result, err := svc.LaunchReaction("test-request-id", "", map[string]string{}, reaction)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.InputField{
+		{Name: "event_id", Value: "evt-1"},
+		{Name: "event_link", Value: "https://calendar/evt-1"},
+		{Name: "first_attendee", Value: "a@b.c"},
+	}, result.Outputs)
+}
+
+func TestAreaService_LaunchReaction_MissingOutput(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"evt-1"}`))
+	}))
+	defer server.Close()
+
+	svc := NewAreaService(new(MockAreaRepository), "test-secret")
+	reaction := domain.ReactionConfig{
+		Url:            server.URL,
+		Method:         "POST",
+		OutputMappings: []domain.ReactionOutputMapping{{FieldKey: "event_link", JSONPath: "$.htmlLink"}},
+	}
+
+	result, err := svc.LaunchReaction("test-request-id", "", map[string]string{}, reaction)
 
 	assert.Error(t, err)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.False(t, Retryable(result, err))
+}
+
+func TestRecordable(t *testing.T) {
+	assert.Equal(t, "abc", recordable("abc", 10))
+	assert.Equal(t, "ab...", recordable("abcdef", 2))
+	assert.Equal(t, "a...", recordable("a\u00e9", 2))
+	assert.Equal(t, "ab", recordable("a\x00b", 10))
 }
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
+	"github.com/raphael-guer1n/AREA/Shared/template"
+)
+
+var ErrInvalidReactionReference = errors.New("invalid reaction reference")
+
+// maxMappedResponse bounds the response read from a reaction whose outputs
+// are mapped.
+const maxMappedResponse = 1 << 20
+
+// reactionKeyRegexp matches the placeholder keys reactions.<n>.<field>, n
+// being the position (from 0) of an earlier reaction of the area.
+var reactionKeyRegexp = regexp.MustCompile(`^reactions\.(\d+)\.([A-Za-z0-9_]+)$`)
+
+type reactionReference struct {
+	position int
+	field    string
+}
+
+func reactionReferences(reaction domain.AreaReaction) []reactionReference {
+	var references []reactionReference
+	for _, input := range reaction.Input {
+		for _, placeholder := range placeholderRegexp.FindAllStringSubmatch(input.Value, -1) {
+			expression, err := template.Parse(placeholder[1])
+			if err != nil {
+				continue
+			}
+			match := reactionKeyRegexp.FindStringSubmatch(expression.Key)
+			if match == nil {
+				continue
+			}
+			position, err := strconv.Atoi(match[1])
+			if err != nil {
+				continue
+			}
+			references = append(references, reactionReference{position: position, field: match[2]})
+		}
+	}
+	return references
+}
+
+// ChainsReactions reports whether the reaction uses the outputs of earlier
+// reactions, and so has to wait for them.
+func ChainsReactions(reaction domain.AreaReaction) bool {
+	return len(reactionReferences(reaction)) > 0
+}
+
+// ValidateReactionReferences checks, when the area is saved, that the reaction
+// at the given position only refers to the declared outputs of the reactions
+// before it. configs are the configurations of the area reactions, in order.
+func ValidateReactionReferences(position int, reaction domain.AreaReaction, configs []domain.ReactionConfig) error {
+	for _, reference := range reactionReferences(reaction) {
+		if reference.position >= position {
+			return fmt.Errorf("%w: reactions.%d is not an earlier reaction", ErrInvalidReactionReference, reference.position)
+		}
+		declared := false
+		for _, mapping := range configs[reference.position].OutputMappings {
+			if mapping.FieldKey == reference.field {
+				declared = true
+				break
+			}
+		}
+		if !declared {
+			return fmt.Errorf("%w: reaction %s has no output %s", ErrInvalidReactionReference, configs[reference.position].Title, reference.field)
+		}
+	}
+	return nil
+}
+
+// ChainedFields turns the outputs of the earlier reactions the reaction refers
+// to into {{reactions.<n>.<field>}} fields. outputs are keyed by position.
+func ChainedFields(reaction domain.AreaReaction, outputs map[int][]domain.InputField) ([]domain.InputField, error) {
+	var fields []domain.InputField
+	for _, reference := range reactionReferences(reaction) {
+		found := false
+		for _, output := range outputs[reference.position] {
+			if output.Name == reference.field {
+				fields = append(fields, domain.InputField{
+					Name:  fmt.Sprintf("reactions.%d.%s", reference.position, reference.field),
+					Value: output.Value,
+				})
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("reactions.%d has no output %s for reaction %d", reference.position, reference.field, reaction.ID)
+		}
+	}
+	return fields, nil
+}
+
+// extractOutputs maps the JSON response of a reaction to its output fields.
+func extractOutputs(body []byte, mappings []domain.ReactionOutputMapping) ([]domain.InputField, error) {
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("response is not JSON: %w", err)
+	}
+
+	outputs := make([]domain.InputField, 0, len(mappings))
+	for _, mapping := range mappings {
+		value, ok := extractJSONPath(data, mapping.JSONPath)
+		if !ok {
+			if mapping.Optional {
+				continue
+			}
+			return nil, fmt.Errorf("missing json path %s", mapping.JSONPath)
+		}
+		outputs = append(outputs, domain.InputField{Name: mapping.FieldKey, Value: stringifyOutput(value)})
+	}
+	return outputs, nil
+}
+
+func stringifyOutput(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	case map[string]any, []any:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(encoded)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// extractJSONPath resolves the dotted paths with indexes ($.items[0].id) used
+// by the PollingService mappings.
+func extractJSONPath(data any, path string) (any, bool) {
+	path = strings.TrimSpace(path)
+	path = strings.TrimPrefix(path, "$")
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return data, true
+	}
+
+	current := data
+	for _, segment := range strings.Split(path, ".") {
+		if segment == "" {
+			return nil, false
+		}
+		key := segment
+		index := ""
+		if bracket := strings.IndexRune(segment, '['); bracket != -1 {
+			key, index = segment[:bracket], segment[bracket:]
+		}
+		if key != "" {
+			obj, ok := current.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			if current, ok = obj[key]; !ok {
+				return nil, false
+			}
+		}
+		for index != "" {
+			end := strings.IndexRune(index, ']')
+			if !strings.HasPrefix(index, "[") || end == -1 {
+				return nil, false
+			}
+			idx, err := strconv.Atoi(index[1:end])
+			if err != nil {
+				return nil, false
+			}
+			arr, ok := current.([]any)
+			if !ok || idx < 0 || idx >= len(arr) {
+				return nil, false
+			}
+			current = arr[idx]
+			index = index[end+1:]
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractJSONPath(t *testing.T) {
+	data := map[string]any{
+		"id": "1",
+		"metadata": map[string]any{
+			"path": "/a",
+			"tags": []any{"x", map[string]any{"name": "y"}},
+		},
+		"matrix": []any{[]any{"a", "b"}},
+	}
+
+	testCases := []struct {
+		path     string
+		expected any
+		found    bool
+	}{
+		{"$.id", "1", true},
+		{"metadata.path", "/a", true},
+		{"$.metadata.tags[0]", "x", true},
+		{"$.metadata.tags[1].name", "y", true},
+		{"$.matrix[0][1]", "b", true},
+		{"$.metadata.tags[2]", nil, false},
+		{"$.missing", nil, false},
+		{"$.id.nested", nil, false},
+		{"$..id", nil, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			value, found := extractJSONPath(data, tc.path)
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.expected, value)
+		})
+	}
+}
+
+func TestExtractOutputs(t *testing.T) {
+	mappings := []domain.ReactionOutputMapping{
+		{FieldKey: "id", JSONPath: "$.id"},
+		{FieldKey: "count", JSONPath: "$.count"},
+		{FieldKey: "labels", JSONPath: "$.labels"},
+		{FieldKey: "note", JSONPath: "$.note", Optional: true},
+	}
+
+	outputs, err := extractOutputs([]byte(`{"id":"42","count":3,"labels":["bug"]}`), mappings)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.InputField{
+		{Name: "id", Value: "42"},
+		{Name: "count", Value: "3"},
+		{Name: "labels", Value: `["bug"]`},
+	}, outputs)
+
+	_, err = extractOutputs([]byte(`{"count":3}`), mappings)
+	assert.Error(t, err)
+
+	_, err = extractOutputs([]byte(`not json`), mappings)
+	assert.Error(t, err)
+}
+
+func TestValidateReactionReferences(t *testing.T) {
+	configs := []domain.ReactionConfig{
+		{Title: "create_event", OutputMappings: []domain.ReactionOutputMapping{{FieldKey: "event_link", JSONPath: "$.htmlLink"}}},
+		{Title: "send_webhook_message"},
+	}
+	reactionUsing := func(value string) domain.AreaReaction {
+		return domain.AreaReaction{Input: []domain.InputField{{Name: "content", Value: value}}}
+	}
+
+	testCases := []struct {
+		name     string
+		position int
+		reaction domain.AreaReaction
+		valid    bool
+	}{
+		{"no reference", 0, reactionUsing("hello {{title}}"), true},
+		{"earlier output", 1, reactionUsing("see {{reactions.0.event_link}}"), true},
+		{"earlier output with filters", 1, reactionUsing("{{ reactions.0.event_link | urlencode }}"), true},
+		{"itself", 0, reactionUsing("{{reactions.0.event_link}}"), false},
+		{"later reaction", 0, reactionUsing("{{reactions.1.event_link}}"), false},
+		{"undeclared output", 1, reactionUsing("{{reactions.0.event_id}}"), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateReactionReferences(tc.position, tc.reaction, configs)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidReactionReference)
+			}
+		})
+	}
+}
+
+func TestChainedFields(t *testing.T) {
+	reaction := domain.AreaReaction{Input: []domain.InputField{
+		{Name: "content", Value: "{{reactions.0.event_link | upper}} ({{reactions.1.folder_id}})"},
+	}}
+	outputs := map[int][]domain.InputField{
+		0: {{Name: "event_link", Value: "https://cal/abc"}},
+		1: {{Name: "folder_id", Value: "id:42"}},
+	}
+
+	fields, err := ChainedFields(reaction, outputs)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.InputField{
+		{Name: "reactions.0.event_link", Value: "https://cal/abc"},
+		{Name: "reactions.1.folder_id", Value: "id:42"},
+	}, fields)
+
+	delete(outputs, 1)
+	_, err = ChainedFields(reaction, outputs)
+	assert.Error(t, err)
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
+)
+
+var ErrInvalidCondition = errors.New("invalid reaction condition")
+
+const maxConditionDepth = 5
+
+// operatorAliases maps the symbolic operators to the names used by the
+// PollingService filters.
+var operatorAliases = map[string]string{
+	"":   "equals",
+	"==": "equals",
+	"!=": "not_equals",
+	">":  "gt",
+	">=": "gte",
+	"<":  "lt",
+	"<=": "lte",
+}
+
+func conditionOperator(condition domain.ReactionCondition) string {
+	operator := strings.ToLower(strings.TrimSpace(condition.Operator))
+	if alias, ok := operatorAliases[operator]; ok {
+		return alias
+	}
+	return operator
+}
+
+func isConditionGroup(condition domain.ReactionCondition) bool {
+	return len(condition.Conditions) > 0 || condition.Mode != ""
+}
+
+// ValidateCondition checks a reaction condition when the area is saved. When
+// outputs is not empty, the fields compared must be among them.
+func ValidateCondition(condition *domain.ReactionCondition, outputs map[string]bool) error {
+	if condition == nil {
+		return nil
+	}
+	if err := validateCondition(*condition, outputs, 1); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCondition, err)
+	}
+	return nil
+}
+
+func validateCondition(condition domain.ReactionCondition, outputs map[string]bool, depth int) error {
+	if depth > maxConditionDepth {
+		return fmt.Errorf("conditions are nested deeper than %d levels", maxConditionDepth)
+	}
+
+	if isConditionGroup(condition) {
+		if condition.Field != "" || condition.Operator != "" {
+			return errors.New("a group cannot have a field or an operator")
+		}
+		switch strings.ToLower(strings.TrimSpace(condition.Mode)) {
+		case "", "all", "any":
+		default:
+			return fmt.Errorf("unknown mode %q, expected all or any", condition.Mode)
+		}
+		if len(condition.Conditions) == 0 {
+			return errors.New("a group needs at least one condition")
+		}
+		for _, child := range condition.Conditions {
+			if err := validateCondition(child, outputs, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	field := strings.TrimSpace(condition.Field)
+	if field == "" {
+		return errors.New("a rule needs a field")
+	}
+	if len(outputs) > 0 && !outputs[field] {
+		return fmt.Errorf("field %s is not an output of the area actions", field)
+	}
+
+	switch operator := conditionOperator(condition); operator {
+	case "exists":
+		return nil
+	case "equals", "not_equals", "contains", "not_contains":
+		if condition.Value == nil {
+			return fmt.Errorf("operator %s on %s needs a value", operator, field)
+		}
+	case "in":
+		if len(condition.Values) == 0 && condition.Value == nil {
+			return fmt.Errorf("operator in on %s needs values", field)
+		}
+	case "regex":
+		pattern := fmt.Sprint(condition.Value)
+		if condition.Value == nil || pattern == "" {
+			return fmt.Errorf("operator regex on %s needs a pattern", field)
+		}
+		if _, err := compileRegex(pattern, condition.CaseInsensitive); err != nil {
+			return fmt.Errorf("invalid pattern on %s: %v", field, err)
+		}
+	case "gt", "gte", "lt", "lte":
+		if _, ok := toFloat(condition.Value); !ok {
+			return fmt.Errorf("operator %s on %s needs a number", operator, field)
+		}
+	default:
+		return fmt.Errorf("unknown operator %q", condition.Operator)
+	}
+	return nil
+}
+
+// MatchCondition evaluates a reaction condition against the output fields of
+// a trigger. A reaction without condition always runs.
+func MatchCondition(condition *domain.ReactionCondition, fields []domain.InputField) bool {
+	if condition == nil {
+		return true
+	}
+	values := make(map[string]string, len(fields))
+	for _, field := range fields {
+		values[field.Name] = field.Value
+	}
+	return matchCondition(*condition, values)
+}
+
+func matchCondition(condition domain.ReactionCondition, values map[string]string) bool {
+	if !isConditionGroup(condition) {
+		return matchesRule(condition, values)
+	}
+	switch strings.ToLower(strings.TrimSpace(condition.Mode)) {
+	case "any":
+		for _, child := range condition.Conditions {
+			if matchCondition(child, values) {
+				return true
+			}
+		}
+		return false
+	default:
+		for _, child := range condition.Conditions {
+			if !matchCondition(child, values) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// matchesRule follows the semantics of the PollingService filter rules, with
+// the negated operators added.
+func matchesRule(rule domain.ReactionCondition, values map[string]string) bool {
+	value, ok := values[strings.TrimSpace(rule.Field)]
+	operator := conditionOperator(rule)
+
+	switch operator {
+	case "exists":
+		return ok
+	case "not_equals":
+		return !ok || !compareString(value, rule.Value, rule.CaseInsensitive)
+	case "not_contains":
+		return !ok || !strings.Contains(normalizeString(value, rule.CaseInsensitive), normalizeString(rule.Value, rule.CaseInsensitive))
+	}
+	if !ok {
+		return false
+	}
+
+	switch operator {
+	case "equals":
+		return compareString(value, rule.Value, rule.CaseInsensitive)
+	case "contains":
+		left := normalizeString(value, rule.CaseInsensitive)
+		right := normalizeString(rule.Value, rule.CaseInsensitive)
+		return left != "" && strings.Contains(left, right)
+	case "in":
+		candidates := rule.Values
+		if len(candidates) == 0 && rule.Value != nil {
+			candidates = []any{rule.Value}
+		}
+		for _, candidate := range candidates {
+			if compareString(value, candidate, rule.CaseInsensitive) {
+				return true
+			}
+		}
+		return false
+	case "regex":
+		pattern := fmt.Sprint(rule.Value)
+		if pattern == "" {
+			return false
+		}
+		re, err := compileRegex(pattern, rule.CaseInsensitive)
+		if err != nil {
+			return false
+		}
+		return re.MatchString(value)
+	case "gt", "gte", "lt", "lte":
+		left, okLeft := toFloat(value)
+		right, okRight := toFloat(rule.Value)
+		if !okLeft || !okRight {
+			return false
+		}
+		switch operator {
+		case "gt":
+			return left > right
+		case "gte":
+			return left >= right
+		case "lt":
+			return left < right
+		case "lte":
+			return left <= right
+		}
+	}
+	return false
+}
+
+func normalizeString(value any, caseInsensitive bool) string {
+	str := fmt.Sprint(value)
+	if caseInsensitive {
+		return strings.ToLower(str)
+	}
+	return str
+}
+
+func compareString(left any, right any, caseInsensitive bool) bool {
+	return normalizeString(left, caseInsensitive) == normalizeString(right, caseInsensitive)
+}
+
+func compileRegex(pattern string, caseInsensitive bool) (*regexp.Regexp, error) {
+	if caseInsensitive {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case string:
+		trimmed := strings.TrimSpace(v)
+		if trimmed == "" {
+			return 0, false
+		}
+		f, err := strconv.ParseFloat(trimmed, 64)
+		if err != nil {
+			return 0, false
+		}
+		return f, true
+	default:
+		return 0, false
+	}
+}
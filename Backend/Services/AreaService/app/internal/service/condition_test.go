@@ -0,0 +1,104 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchCondition(t *testing.T) {
+	fields := []domain.InputField{
+		{Name: "title", Value: "Release v2.0"},
+		{Name: "temp_c", Value: "31.5"},
+		{Name: "author", Value: "octocat"},
+	}
+
+	testCases := []struct {
+		name      string
+		condition *domain.ReactionCondition
+		expected  bool
+	}{
+		{"no condition", nil, true},
+		{"contains", &domain.ReactionCondition{Field: "title", Operator: "contains", Value: "Release"}, true},
+		{"contains is case sensitive", &domain.ReactionCondition{Field: "title", Operator: "contains", Value: "release"}, false},
+		{"contains case insensitive", &domain.ReactionCondition{Field: "title", Operator: "contains", Value: "release", CaseInsensitive: true}, true},
+		{"equals by default", &domain.ReactionCondition{Field: "author", Value: "octocat"}, true},
+		{"not equals", &domain.ReactionCondition{Field: "author", Operator: "!=", Value: "dependabot"}, true},
+		{"not equals on a missing field", &domain.ReactionCondition{Field: "missing", Operator: "not_equals", Value: "x"}, true},
+		{"not contains", &domain.ReactionCondition{Field: "title", Operator: "not_contains", Value: "beta"}, true},
+		{"greater than", &domain.ReactionCondition{Field: "temp_c", Operator: ">", Value: float64(30)}, true},
+		{"less or equal", &domain.ReactionCondition{Field: "temp_c", Operator: "lte", Value: "30"}, false},
+		{"number on text", &domain.ReactionCondition{Field: "title", Operator: "gt", Value: float64(1)}, false},
+		{"in", &domain.ReactionCondition{Field: "author", Operator: "in", Values: []any{"alice", "octocat"}}, true},
+		{"regex", &domain.ReactionCondition{Field: "title", Operator: "regex", Value: `^release v\d`, CaseInsensitive: true}, true},
+		{"exists", &domain.ReactionCondition{Field: "author", Operator: "exists"}, true},
+		{"missing field", &domain.ReactionCondition{Field: "missing", Operator: "contains", Value: "x"}, false},
+		{"all", &domain.ReactionCondition{Conditions: []domain.ReactionCondition{
+			{Field: "title", Operator: "contains", Value: "Release"},
+			{Field: "author", Operator: "equals", Value: "dependabot"},
+		}}, false},
+		{"any", &domain.ReactionCondition{Mode: "any", Conditions: []domain.ReactionCondition{
+			{Field: "title", Operator: "contains", Value: "Release"},
+			{Field: "author", Operator: "equals", Value: "dependabot"},
+		}}, true},
+		{"nested", &domain.ReactionCondition{Conditions: []domain.ReactionCondition{
+			{Field: "temp_c", Operator: "gt", Value: float64(30)},
+			{Mode: "any", Conditions: []domain.ReactionCondition{
+				{Field: "author", Operator: "equals", Value: "alice"},
+				{Field: "title", Operator: "regex", Value: "v2"},
+			}},
+		}}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, MatchCondition(tc.condition, fields))
+		})
+	}
+}
+
+func TestValidateCondition(t *testing.T) {
+	outputs := map[string]bool{"title": true, "temp_c": true}
+
+	deep := domain.ReactionCondition{Field: "title", Operator: "exists"}
+	for i := 0; i < maxConditionDepth; i++ {
+		deep = domain.ReactionCondition{Conditions: []domain.ReactionCondition{deep}}
+	}
+
+	testCases := []struct {
+		name      string
+		condition *domain.ReactionCondition
+		outputs   map[string]bool
+		valid     bool
+	}{
+		{"no condition", nil, outputs, true},
+		{"valid rule", &domain.ReactionCondition{Field: "temp_c", Operator: ">", Value: float64(30)}, outputs, true},
+		{"valid group", &domain.ReactionCondition{Mode: "any", Conditions: []domain.ReactionCondition{
+			{Field: "title", Operator: "contains", Value: "release"},
+			{Field: "temp_c", Operator: "lt", Value: "0"},
+		}}, outputs, true},
+		{"unknown field", &domain.ReactionCondition{Field: "author", Operator: "exists"}, outputs, false},
+		{"any field without outputs", &domain.ReactionCondition{Field: "author", Operator: "exists"}, nil, true},
+		{"missing field", &domain.ReactionCondition{Operator: "exists"}, outputs, false},
+		{"unknown operator", &domain.ReactionCondition{Field: "title", Operator: "like", Value: "x"}, outputs, false},
+		{"missing value", &domain.ReactionCondition{Field: "title", Operator: "contains"}, outputs, false},
+		{"number expected", &domain.ReactionCondition{Field: "temp_c", Operator: "gt", Value: "hot"}, outputs, false},
+		{"invalid regex", &domain.ReactionCondition{Field: "title", Operator: "regex", Value: "("}, outputs, false},
+		{"unknown mode", &domain.ReactionCondition{Mode: "xor", Conditions: []domain.ReactionCondition{{Field: "title", Operator: "exists"}}}, outputs, false},
+		{"empty group", &domain.ReactionCondition{Mode: "all"}, outputs, false},
+		{"group with a field", &domain.ReactionCondition{Field: "title", Conditions: []domain.ReactionCondition{{Field: "title", Operator: "exists"}}}, outputs, false},
+		{"too deep", &deep, outputs, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateCondition(tc.condition, tc.outputs)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidCondition)
+			}
+		})
+	}
+}
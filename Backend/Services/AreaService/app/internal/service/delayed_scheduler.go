@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
+)
+
+const (
+	delayedPollInterval = time.Second
+	// delayedStaleAfter is how long a claimed trigger may run before another
+	// replica takes it over.
+	delayedStaleAfter = 10 * time.Minute
+	// delayedMaxAttempts stops a trigger that keeps bringing its replica down
+	// from being claimed forever.
+	delayedMaxAttempts = 3
+)
+
+// DelayedRunner runs a due trigger.
+type DelayedRunner func(trigger domain.DelayedTrigger) error
+
+// DelayedScheduler runs the delayed triggers once they are due. Triggers are
+// stored, so they survive restarts and are shared by the replicas; a failed
+// trigger is recorded instead of stopping the service.
+type DelayedScheduler struct {
+	repo domain.DelayedTriggerRepository
+}
+
+func NewDelayedScheduler(repo domain.DelayedTriggerRepository) *DelayedScheduler {
+	return &DelayedScheduler{repo: repo}
+}
+
+// ScheduleCronAction schedules the trigger of a cron action with its output
+// fields, replacing the one still pending.
+func (s *DelayedScheduler) ScheduleCronAction(actionID int, outputFields []domain.InputField, delay time.Duration) (domain.DelayedTrigger, error) {
+	payload, err := json.Marshal(outputFields)
+	if err != nil {
+		return domain.DelayedTrigger{}, err
+	}
+	trigger, err := s.repo.Schedule(domain.DelayedTrigger{
+		Kind:     domain.DelayedCronAction,
+		ActionID: actionID,
+		Payload:  payload,
+	}, delay)
+	if err != nil {
+		return trigger, fmt.Errorf("error scheduling action %d: %w", actionID, err)
+	}
+	return trigger, nil
+}
+
+// ScheduleCalendarEvent schedules the creation of a calendar event of the user.
+func (s *DelayedScheduler) ScheduleCalendarEvent(userID int, event domain.Event, delay time.Duration) (domain.DelayedTrigger, error) {
+	payload, err := json.Marshal(domain.CalendarEventPayload{UserID: userID, Event: event})
+	if err != nil {
+		return domain.DelayedTrigger{}, err
+	}
+	trigger, err := s.repo.Schedule(domain.DelayedTrigger{
+		Kind:    domain.DelayedCalendarEvent,
+		Payload: payload,
+	}, delay)
+	if err != nil {
+		return trigger, fmt.Errorf("error scheduling calendar event: %w", err)
+	}
+	return trigger, nil
+}
+
+// Start polls for due triggers until ctx is done.
+func (s *DelayedScheduler) Start(ctx context.Context, run DelayedRunner) {
+	go func() {
+		for {
+			trigger, err := s.repo.Claim(delayedStaleAfter)
+			if err != nil {
+				log.Printf("failed to claim delayed trigger: %v", err)
+			}
+			if trigger == nil {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(delayedPollInterval):
+				}
+				continue
+			}
+			s.process(*trigger, run)
+		}
+	}()
+}
+
+func (s *DelayedScheduler) process(trigger domain.DelayedTrigger, run DelayedRunner) {
+	var err error
+	if trigger.Attempts > delayedMaxAttempts {
+		err = fmt.Errorf("abandoned after %d attempts", delayedMaxAttempts)
+	} else {
+		err = runDelayed(trigger, run)
+	}
+
+	if err == nil {
+		err = s.repo.Complete(trigger.ID)
+	} else {
+		log.Printf("Delayed %s trigger %d failed: %v", trigger.Kind, trigger.ID, err)
+		err = s.repo.Fail(trigger.ID, err.Error())
+	}
+	if err != nil {
+		log.Printf("failed to update delayed trigger %d: %v", trigger.ID, err)
+	}
+}
+
+// runDelayed turns a panic of the runner into the failure of the trigger.
+func runDelayed(trigger domain.DelayedTrigger, run DelayedRunner) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return run(trigger)
+}
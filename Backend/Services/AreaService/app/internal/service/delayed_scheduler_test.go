@@ -0,0 +1,128 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDelayedTriggerRepository is a mock implementation of DelayedTriggerRepository
+type MockDelayedTriggerRepository struct {
+	mock.Mock
+}
+
+func (m *MockDelayedTriggerRepository) Schedule(trigger domain.DelayedTrigger, delay time.Duration) (domain.DelayedTrigger, error) {
+	args := m.Called(trigger, delay)
+	return args.Get(0).(domain.DelayedTrigger), args.Error(1)
+}
+
+func (m *MockDelayedTriggerRepository) Claim(staleAfter time.Duration) (*domain.DelayedTrigger, error) {
+	args := m.Called(staleAfter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DelayedTrigger), args.Error(1)
+}
+
+func (m *MockDelayedTriggerRepository) Complete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockDelayedTriggerRepository) Fail(id int, lastError string) error {
+	args := m.Called(id, lastError)
+	return args.Error(0)
+}
+
+func TestDelayedScheduler_ScheduleCronAction(t *testing.T) {
+	repo := new(MockDelayedTriggerRepository)
+	scheduler := NewDelayedScheduler(repo)
+	outputFields := []domain.InputField{{Name: "delay", Value: "60"}}
+
+	repo.On("Schedule", mock.MatchedBy(func(trigger domain.DelayedTrigger) bool {
+		var payload []domain.InputField
+		return trigger.Kind == domain.DelayedCronAction && trigger.ActionID == 5 &&
+			json.Unmarshal(trigger.Payload, &payload) == nil && assert.ObjectsAreEqual(outputFields, payload)
+	}), time.Minute).Return(domain.DelayedTrigger{ID: 1}, nil)
+
+	trigger, err := scheduler.ScheduleCronAction(5, outputFields, time.Minute)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, trigger.ID)
+	repo.AssertExpectations(t)
+}
+
+func TestDelayedScheduler_ScheduleCalendarEvent(t *testing.T) {
+	repo := new(MockDelayedTriggerRepository)
+	scheduler := NewDelayedScheduler(repo)
+	event := domain.Event{Summary: "Standup"}
+
+	repo.On("Schedule", mock.MatchedBy(func(trigger domain.DelayedTrigger) bool {
+		var payload domain.CalendarEventPayload
+		return trigger.Kind == domain.DelayedCalendarEvent && trigger.ActionID == 0 &&
+			json.Unmarshal(trigger.Payload, &payload) == nil && payload.UserID == 42 && payload.Event.Summary == "Standup"
+	}), 30*time.Second).Return(domain.DelayedTrigger{}, errors.New("database error"))
+
+	_, err := scheduler.ScheduleCalendarEvent(42, event, 30*time.Second)
+
+	assert.Error(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestDelayedScheduler_Process(t *testing.T) {
+	trigger := domain.DelayedTrigger{ID: 3, Kind: domain.DelayedCronAction, Attempts: 1}
+
+	t.Run("success", func(t *testing.T) {
+		repo := new(MockDelayedTriggerRepository)
+		scheduler := NewDelayedScheduler(repo)
+		repo.On("Complete", 3).Return(nil)
+
+		scheduler.process(trigger, func(domain.DelayedTrigger) error { return nil })
+
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("failure is recorded", func(t *testing.T) {
+		repo := new(MockDelayedTriggerRepository)
+		scheduler := NewDelayedScheduler(repo)
+		repo.On("Fail", 3, "triggerArea returned status 500").Return(nil)
+
+		scheduler.process(trigger, func(domain.DelayedTrigger) error {
+			return errors.New("triggerArea returned status 500")
+		})
+
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("panic is recorded", func(t *testing.T) {
+		repo := new(MockDelayedTriggerRepository)
+		scheduler := NewDelayedScheduler(repo)
+		repo.On("Fail", 3, "panic: boom").Return(nil)
+
+		scheduler.process(trigger, func(domain.DelayedTrigger) error { panic("boom") })
+
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("abandoned after too many attempts", func(t *testing.T) {
+		repo := new(MockDelayedTriggerRepository)
+		scheduler := NewDelayedScheduler(repo)
+		repo.On("Fail", 3, mock.AnythingOfType("string")).Return(nil)
+		stale := trigger
+		stale.Attempts = delayedMaxAttempts + 1
+
+		ran := false
+		scheduler.process(stale, func(domain.DelayedTrigger) error {
+			ran = true
+			return nil
+		})
+
+		assert.False(t, ran)
+		repo.AssertExpectations(t)
+	})
+}
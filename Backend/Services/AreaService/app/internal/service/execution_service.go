@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
+)
+
+const (
+	DefaultExecutionPageSize = 20
+	MaxExecutionPageSize     = 100
+)
+
+// ExecutionService keeps the history of the area triggers.
+type ExecutionService struct {
+	repo      domain.ExecutionRepository
+	retention time.Duration
+}
+
+func NewExecutionService(repo domain.ExecutionRepository, retention time.Duration) *ExecutionService {
+	return &ExecutionService{repo: repo, retention: retention}
+}
+
+// Record stores the execution, deriving its status from the reactions when
+// none is set.
+func (s *ExecutionService) Record(execution domain.Execution) (domain.Execution, error) {
+	if execution.Status == "" {
+		execution.Status = domain.ExecutionSuccess
+		for _, reaction := range execution.Reactions {
+			if reaction.Error != "" {
+				execution.Status = domain.ExecutionFailed
+				break
+			}
+		}
+	}
+	if execution.OutputFields == nil {
+		execution.OutputFields = []domain.InputField{}
+	}
+
+	stored, err := s.repo.Create(execution)
+	if err != nil {
+		return execution, fmt.Errorf("error recording execution: %w", err)
+	}
+	return stored, nil
+}
+
+// RecordReaction appends one reaction attempt to a queued execution.
+func (s *ExecutionService) RecordReaction(executionID int, reaction domain.ReactionExecution) error {
+	if err := s.repo.AddReaction(executionID, reaction); err != nil {
+		return fmt.Errorf("error recording reaction: %w", err)
+	}
+	return nil
+}
+
+// ChainedFields returns the outputs of the earlier reactions of the execution
+// that the reaction refers to, as {{reactions.<n>.<field>}} fields.
+func (s *ExecutionService) ChainedFields(executionID int, reactions []domain.AreaReaction, reaction domain.AreaReaction) ([]domain.InputField, error) {
+	if !ChainsReactions(reaction) {
+		return nil, nil
+	}
+	byReaction, err := s.repo.ReactionOutputs(executionID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: loading reaction outputs: %v", ErrRetryable, err)
+	}
+	outputs := make(map[int][]domain.InputField, len(byReaction))
+	for position, earlier := range reactions {
+		if fields, ok := byReaction[earlier.ID]; ok {
+			outputs[position] = fields
+		}
+	}
+	return ChainedFields(reaction, outputs)
+}
+
+// ListByArea returns the given page (from 1) of the area executions, most
+// recent first, with the total number of executions.
+func (s *ExecutionService) ListByArea(areaID, page, pageSize int) ([]domain.Execution, int, error) {
+	limit, offset := pageBounds(page, pageSize)
+	executions, total, err := s.repo.ListByArea(areaID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing executions: %w", err)
+	}
+	return executions, total, nil
+}
+
+// pageBounds turns a page (from 1) and a page size into a limit and an offset.
+func pageBounds(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultExecutionPageSize
+	}
+	if pageSize > MaxExecutionPageSize {
+		pageSize = MaxExecutionPageSize
+	}
+	return pageSize, (page - 1) * pageSize
+}
+
+// StartCleanup drops the executions older than the retention period. A zero
+// retention keeps them forever.
+func (s *ExecutionService) StartCleanup(ctx context.Context, interval time.Duration) {
+	if s.retention <= 0 {
+		return
+	}
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.repo.DeleteOlderThan(time.Now().Add(-s.retention))
+			if err != nil {
+				log.Printf("failed to delete old executions: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("deleted %d old executions", deleted)
+			}
+		}
+	}
+}
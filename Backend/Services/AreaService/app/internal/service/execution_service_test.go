@@ -0,0 +1,169 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockExecutionRepository is a mock implementation of ExecutionRepository
+type MockExecutionRepository struct {
+	mock.Mock
+}
+
+func (m *MockExecutionRepository) Create(execution domain.Execution) (domain.Execution, error) {
+	args := m.Called(execution)
+	return args.Get(0).(domain.Execution), args.Error(1)
+}
+
+func (m *MockExecutionRepository) AddReaction(executionID int, reaction domain.ReactionExecution) error {
+	args := m.Called(executionID, reaction)
+	return args.Error(0)
+}
+
+func (m *MockExecutionRepository) ReactionOutputs(executionID int) (map[int][]domain.InputField, error) {
+	args := m.Called(executionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int][]domain.InputField), args.Error(1)
+}
+
+func (m *MockExecutionRepository) ListByArea(areaID int, limit int, offset int) ([]domain.Execution, int, error) {
+	args := m.Called(areaID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]domain.Execution), args.Int(1), args.Error(2)
+}
+
+func (m *MockExecutionRepository) DeleteOlderThan(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestExecutionService_Record_DerivesStatus(t *testing.T) {
+	testCases := []struct {
+		name      string
+		execution domain.Execution
+		expected  string
+	}{
+		{"all reactions succeeded", domain.Execution{Reactions: []domain.ReactionExecution{{ReactionID: 1}, {ReactionID: 2}}}, domain.ExecutionSuccess},
+		{"a reaction failed", domain.Execution{Reactions: []domain.ReactionExecution{{ReactionID: 1}, {ReactionID: 2, Error: "boom"}}}, domain.ExecutionFailed},
+		{"status already set", domain.Execution{Status: domain.ExecutionSkipped}, domain.ExecutionSkipped},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockExecutionRepository)
+			svc := NewExecutionService(mockRepo, 0)
+
+			mockRepo.On("Create", mock.AnythingOfType("domain.Execution")).Return(domain.Execution{ID: 1}, nil)
+
+			_, err := svc.Record(tc.execution)
+
+			assert.NoError(t, err)
+			stored := mockRepo.Calls[0].Arguments.Get(0).(domain.Execution)
+			assert.Equal(t, tc.expected, stored.Status)
+			assert.NotNil(t, stored.OutputFields)
+		})
+	}
+}
+
+func TestExecutionService_Record_Error(t *testing.T) {
+	mockRepo := new(MockExecutionRepository)
+	svc := NewExecutionService(mockRepo, 0)
+
+	dbError := errors.New("database error")
+	mockRepo.On("Create", mock.AnythingOfType("domain.Execution")).Return(domain.Execution{}, dbError)
+
+	_, err := svc.Record(domain.Execution{AreaID: 1})
+
+	assert.ErrorIs(t, err, dbError)
+}
+
+func TestExecutionService_ListByArea_Pagination(t *testing.T) {
+	testCases := []struct {
+		name           string
+		page, pageSize int
+		limit, offset  int
+	}{
+		{"first page", 1, 10, 10, 0},
+		{"third page", 3, 10, 10, 20},
+		{"defaults", 0, 0, DefaultExecutionPageSize, 0},
+		{"page size capped", 2, 1000, MaxExecutionPageSize, MaxExecutionPageSize},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockExecutionRepository)
+			svc := NewExecutionService(mockRepo, 0)
+
+			expected := []domain.Execution{{ID: 1, AreaID: 7}}
+			mockRepo.On("ListByArea", 7, tc.limit, tc.offset).Return(expected, 42, nil)
+
+			executions, total, err := svc.ListByArea(7, tc.page, tc.pageSize)
+
+			assert.NoError(t, err)
+			assert.Equal(t, expected, executions)
+			assert.Equal(t, 42, total)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExecutionService_ChainedFields(t *testing.T) {
+	reactions := []domain.AreaReaction{
+		{ID: 20},
+		{ID: 21, Input: []domain.InputField{{Name: "content", Value: "New event {{reactions.0.event_link}}"}}},
+	}
+
+	t.Run("outputs of earlier reactions", func(t *testing.T) {
+		mockRepo := new(MockExecutionRepository)
+		svc := NewExecutionService(mockRepo, 0)
+		mockRepo.On("ReactionOutputs", 1).Return(map[int][]domain.InputField{
+			20: {{Name: "event_id", Value: "abc"}, {Name: "event_link", Value: "https://cal/abc"}},
+		}, nil)
+
+		fields, err := svc.ChainedFields(1, reactions, reactions[1])
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.InputField{{Name: "reactions.0.event_link", Value: "https://cal/abc"}}, fields)
+	})
+
+	t.Run("reaction without reference", func(t *testing.T) {
+		mockRepo := new(MockExecutionRepository)
+		svc := NewExecutionService(mockRepo, 0)
+
+		fields, err := svc.ChainedFields(1, reactions, reactions[0])
+
+		assert.NoError(t, err)
+		assert.Empty(t, fields)
+		mockRepo.AssertNotCalled(t, "ReactionOutputs", mock.Anything)
+	})
+
+	t.Run("earlier reaction without output", func(t *testing.T) {
+		mockRepo := new(MockExecutionRepository)
+		svc := NewExecutionService(mockRepo, 0)
+		mockRepo.On("ReactionOutputs", 1).Return(map[int][]domain.InputField{}, nil)
+
+		_, err := svc.ChainedFields(1, reactions, reactions[1])
+
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, ErrRetryable))
+	})
+
+	t.Run("database error is retryable", func(t *testing.T) {
+		mockRepo := new(MockExecutionRepository)
+		svc := NewExecutionService(mockRepo, 0)
+		mockRepo.On("ReactionOutputs", 1).Return(nil, errors.New("database error"))
+
+		_, err := svc.ChainedFields(1, reactions, reactions[1])
+
+		assert.ErrorIs(t, err, ErrRetryable)
+	})
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
+)
+
+var (
+	// ErrRetryable marks a reaction failure worth another attempt.
+	ErrRetryable          = errors.New("temporary reaction failure")
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+)
+
+const (
+	jobPollInterval = time.Second
+	// jobStaleAfter is how long a claimed job may run before another worker
+	// takes it over. It is well above the reaction request timeout.
+	jobStaleAfter = 10 * time.Minute
+)
+
+// ReactionRunner runs the reaction of a job and returns the attempt result.
+type ReactionRunner func(job domain.ReactionJob) (domain.ReactionExecution, error)
+
+// ReactionQueue runs the reactions of the triggered areas in the background,
+// each one on its own, retrying the temporary failures with an exponential
+// backoff before moving the job to the dead letters.
+type ReactionQueue struct {
+	repo        domain.ReactionJobRepository
+	executions  *ExecutionService
+	workers     int
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+}
+
+func NewReactionQueue(repo domain.ReactionJobRepository, executions *ExecutionService, workers, maxAttempts int, backoff, maxBackoff time.Duration) *ReactionQueue {
+	if workers < 1 {
+		workers = 1
+	}
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	if maxBackoff < backoff {
+		maxBackoff = backoff
+	}
+	return &ReactionQueue{
+		repo:        repo,
+		executions:  executions,
+		workers:     workers,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+		maxBackoff:  maxBackoff,
+	}
+}
+
+// Enqueue stores a queued execution with one job per reaction, reactions being
+// given in the order of the area. The reactions whose condition does not match
+// the output fields are recorded as skipped.
+func (q *ReactionQueue) Enqueue(execution domain.Execution, reactions []domain.AreaReaction, userID int) (domain.Execution, error) {
+	execution.Status = domain.ExecutionQueued
+	if execution.OutputFields == nil {
+		execution.OutputFields = []domain.InputField{}
+	}
+
+	jobs := make([]domain.ReactionJob, 0, len(reactions))
+	for i, reaction := range reactions {
+		if !MatchCondition(reaction.Condition, execution.OutputFields) {
+			execution.Reactions = append(execution.Reactions, domain.ReactionExecution{ReactionID: reaction.ID, Skipped: true})
+			continue
+		}
+		jobs = append(jobs, domain.ReactionJob{
+			AreaID:       execution.AreaID,
+			ReactionID:   reaction.ID,
+			Position:     i,
+			Chained:      ChainsReactions(reaction),
+			UserID:       userID,
+			RequestID:    execution.RequestID,
+			OutputFields: execution.OutputFields,
+			MaxAttempts:  q.maxAttempts,
+		})
+	}
+
+	stored, err := q.repo.Enqueue(execution, jobs)
+	if err != nil {
+		return execution, fmt.Errorf("error queueing reactions: %w", err)
+	}
+	return stored, nil
+}
+
+// Start runs the workers until ctx is done.
+func (q *ReactionQueue) Start(ctx context.Context, run ReactionRunner) {
+	for i := 0; i < q.workers; i++ {
+		go q.work(ctx, run)
+	}
+}
+
+func (q *ReactionQueue) work(ctx context.Context, run ReactionRunner) {
+	for {
+		job, err := q.repo.Claim(jobStaleAfter)
+		if err != nil {
+			log.Printf("failed to claim reaction job: %v", err)
+		}
+		if job == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(jobPollInterval):
+			}
+			continue
+		}
+		q.process(*job, run)
+	}
+}
+
+func (q *ReactionQueue) process(job domain.ReactionJob, run ReactionRunner) {
+	var (
+		result domain.ReactionExecution
+		err    error
+	)
+	if job.Attempts > job.MaxAttempts {
+		// The job was claimed again after its worker died without finishing it
+		// more times than it may be attempted: it keeps bringing workers down.
+		err = fmt.Errorf("abandoned after %d attempts", job.MaxAttempts)
+	} else {
+		result, err = runReaction(job, run)
+	}
+	result.ReactionID = job.ReactionID
+	result.Attempt = job.Attempts
+	if err != nil && result.Error == "" {
+		result.Error = err.Error()
+	}
+	if recordErr := q.executions.RecordReaction(job.ExecutionID, result); recordErr != nil {
+		log.Printf("Failed to record reaction %d of execution %d (request_id=%s): %v", job.ReactionID, job.ExecutionID, job.RequestID, recordErr)
+	}
+
+	switch {
+	case err == nil:
+		err = q.repo.Complete(job)
+	case !Retryable(result, err) || job.Attempts >= job.MaxAttempts:
+		log.Printf("Reaction %d of area %d failed for good after %d attempts (request_id=%s): %v", job.ReactionID, job.AreaID, job.Attempts, job.RequestID, err)
+		err = q.repo.Bury(job, result.Error, result.StatusCode)
+	default:
+		delay := q.retryDelay(job.Attempts)
+		log.Printf("Reaction %d of area %d failed, retrying in %s (request_id=%s): %v", job.ReactionID, job.AreaID, delay, job.RequestID, err)
+		err = q.repo.Retry(job, delay, result.Error)
+	}
+	if err != nil {
+		log.Printf("failed to update reaction job %d: %v", job.ID, err)
+	}
+}
+
+// runReaction turns a panic of the runner into the failure of the attempt.
+func runReaction(job domain.ReactionJob, run ReactionRunner) (result domain.ReactionExecution, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result, err = domain.ReactionExecution{}, fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return run(job)
+}
+
+// retryDelay doubles the backoff at each attempt up to the maximum, plus up to
+// 20% of jitter so that the jobs failing together do not retry together.
+func (q *ReactionQueue) retryDelay(attempt int) time.Duration {
+	delay := q.backoff
+	for i := 1; i < attempt && delay < q.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > q.maxBackoff {
+		delay = q.maxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay + time.Duration(rand.Int64N(int64(delay)/5+1))
+}
+
+// Retryable tells whether a failed reaction may succeed later: the endpoint
+// was rate limited, failed on its side or could not be reached.
+func Retryable(result domain.ReactionExecution, err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrRetryable) {
+		return true
+	}
+	if result.StatusCode == http.StatusTooManyRequests || result.StatusCode >= 500 {
+		return true
+	}
+	var urlErr *url.Error
+	return result.StatusCode == 0 && errors.As(err, &urlErr)
+}
+
+// DeadLetters returns the given page (from 1) of the user dead letters, of one
+// area when areaID is not 0, with their total number.
+func (q *ReactionQueue) DeadLetters(userID, areaID, page, pageSize int) ([]domain.DeadLetter, int, error) {
+	limit, offset := pageBounds(page, pageSize)
+	letters, total, err := q.repo.ListDeadLetters(userID, areaID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing dead letters: %w", err)
+	}
+	return letters, total, nil
+}
+
+// Replay queues a dead letter of the user again with a full attempt budget.
+func (q *ReactionQueue) Replay(userID, id int) (domain.ReactionJob, error) {
+	letter, err := q.repo.GetDeadLetter(id)
+	if err != nil {
+		return domain.ReactionJob{}, fmt.Errorf("error getting dead letter: %w", err)
+	}
+	if letter == nil || letter.UserID != userID {
+		return domain.ReactionJob{}, ErrDeadLetterNotFound
+	}
+
+	job, err := q.repo.Replay(*letter, q.maxAttempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ReactionJob{}, ErrDeadLetterNotFound
+	}
+	if err != nil {
+		return domain.ReactionJob{}, fmt.Errorf("error replaying dead letter: %w", err)
+	}
+	return job, nil
+}
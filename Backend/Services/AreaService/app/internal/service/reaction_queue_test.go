@@ -0,0 +1,315 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/AreaService/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReactionJobRepository is a mock implementation of ReactionJobRepository
+type MockReactionJobRepository struct {
+	mock.Mock
+}
+
+func (m *MockReactionJobRepository) Enqueue(execution domain.Execution, jobs []domain.ReactionJob) (domain.Execution, error) {
+	args := m.Called(execution, jobs)
+	return args.Get(0).(domain.Execution), args.Error(1)
+}
+
+func (m *MockReactionJobRepository) Claim(staleAfter time.Duration) (*domain.ReactionJob, error) {
+	args := m.Called(staleAfter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ReactionJob), args.Error(1)
+}
+
+func (m *MockReactionJobRepository) Complete(job domain.ReactionJob) error {
+	args := m.Called(job)
+	return args.Error(0)
+}
+
+func (m *MockReactionJobRepository) Retry(job domain.ReactionJob, delay time.Duration, lastError string) error {
+	args := m.Called(job, delay, lastError)
+	return args.Error(0)
+}
+
+func (m *MockReactionJobRepository) Bury(job domain.ReactionJob, lastError string, lastStatusCode int) error {
+	args := m.Called(job, lastError, lastStatusCode)
+	return args.Error(0)
+}
+
+func (m *MockReactionJobRepository) ListDeadLetters(userID int, areaID int, limit int, offset int) ([]domain.DeadLetter, int, error) {
+	args := m.Called(userID, areaID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]domain.DeadLetter), args.Int(1), args.Error(2)
+}
+
+func (m *MockReactionJobRepository) GetDeadLetter(id int) (*domain.DeadLetter, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DeadLetter), args.Error(1)
+}
+
+func (m *MockReactionJobRepository) Replay(letter domain.DeadLetter, maxAttempts int) (domain.ReactionJob, error) {
+	args := m.Called(letter, maxAttempts)
+	return args.Get(0).(domain.ReactionJob), args.Error(1)
+}
+
+func newTestQueue() (*ReactionQueue, *MockReactionJobRepository, *MockExecutionRepository) {
+	jobRepo := new(MockReactionJobRepository)
+	executionRepo := new(MockExecutionRepository)
+	queue := NewReactionQueue(jobRepo, NewExecutionService(executionRepo, 0), 1, 3, time.Second, 10*time.Second)
+	return queue, jobRepo, executionRepo
+}
+
+func TestReactionQueue_Enqueue(t *testing.T) {
+	queue, jobRepo, _ := newTestQueue()
+
+	execution := domain.Execution{AreaID: 7, ActionID: 3, RequestID: "req-1"}
+	reactions := []domain.AreaReaction{{ID: 10}, {ID: 11}}
+	jobRepo.On("Enqueue", mock.AnythingOfType("domain.Execution"), mock.AnythingOfType("[]domain.ReactionJob")).
+		Return(domain.Execution{ID: 1, Status: domain.ExecutionQueued}, nil)
+
+	stored, err := queue.Enqueue(execution, reactions, 42)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stored.ID)
+	queued := jobRepo.Calls[0].Arguments.Get(0).(domain.Execution)
+	assert.Equal(t, domain.ExecutionQueued, queued.Status)
+	assert.NotNil(t, queued.OutputFields)
+	jobs := jobRepo.Calls[0].Arguments.Get(1).([]domain.ReactionJob)
+	assert.Len(t, jobs, 2)
+	for i, job := range jobs {
+		assert.Equal(t, reactions[i].ID, job.ReactionID)
+		assert.Equal(t, i, job.Position)
+		assert.False(t, job.Chained)
+		assert.Equal(t, 42, job.UserID)
+		assert.Equal(t, 7, job.AreaID)
+		assert.Equal(t, "req-1", job.RequestID)
+		assert.Equal(t, 3, job.MaxAttempts)
+	}
+}
+
+func TestReactionQueue_Enqueue_ChainedReactions(t *testing.T) {
+	queue, jobRepo, _ := newTestQueue()
+
+	reactions := []domain.AreaReaction{
+		{ID: 10},
+		{ID: 11, Input: []domain.InputField{{Name: "content", Value: "{{reactions.0.event_link}}"}}},
+	}
+	jobRepo.On("Enqueue", mock.AnythingOfType("domain.Execution"), mock.AnythingOfType("[]domain.ReactionJob")).
+		Return(domain.Execution{ID: 1}, nil)
+
+	_, err := queue.Enqueue(domain.Execution{AreaID: 7}, reactions, 42)
+
+	assert.NoError(t, err)
+	jobs := jobRepo.Calls[0].Arguments.Get(1).([]domain.ReactionJob)
+	assert.False(t, jobs[0].Chained)
+	assert.True(t, jobs[1].Chained)
+	assert.Equal(t, 1, jobs[1].Position)
+}
+
+func TestReactionQueue_Enqueue_SkipsUnmatchedConditions(t *testing.T) {
+	queue, jobRepo, _ := newTestQueue()
+
+	execution := domain.Execution{AreaID: 7, OutputFields: []domain.InputField{{Name: "author", Value: "dependabot"}}}
+	reactions := []domain.AreaReaction{
+		{ID: 10},
+		{ID: 11, Condition: &domain.ReactionCondition{Field: "author", Operator: "!=", Value: "dependabot"}},
+	}
+	jobRepo.On("Enqueue", mock.AnythingOfType("domain.Execution"), mock.AnythingOfType("[]domain.ReactionJob")).
+		Return(domain.Execution{ID: 1}, nil)
+
+	_, err := queue.Enqueue(execution, reactions, 42)
+
+	assert.NoError(t, err)
+	queued := jobRepo.Calls[0].Arguments.Get(0).(domain.Execution)
+	assert.Equal(t, []domain.ReactionExecution{{ReactionID: 11, Skipped: true}}, queued.Reactions)
+	jobs := jobRepo.Calls[0].Arguments.Get(1).([]domain.ReactionJob)
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, 10, jobs[0].ReactionID)
+}
+
+func TestReactionQueue_Process(t *testing.T) {
+	job := domain.ReactionJob{ID: 5, ExecutionID: 1, AreaID: 7, ReactionID: 10, Attempts: 1, MaxAttempts: 3}
+
+	t.Run("success completes the job", func(t *testing.T) {
+		queue, jobRepo, executionRepo := newTestQueue()
+		executionRepo.On("AddReaction", 1, mock.AnythingOfType("domain.ReactionExecution")).Return(nil)
+		jobRepo.On("Complete", job).Return(nil)
+
+		queue.process(job, func(domain.ReactionJob) (domain.ReactionExecution, error) {
+			return domain.ReactionExecution{StatusCode: 200}, nil
+		})
+
+		jobRepo.AssertExpectations(t)
+		recorded := executionRepo.Calls[0].Arguments.Get(1).(domain.ReactionExecution)
+		assert.Equal(t, 10, recorded.ReactionID)
+		assert.Equal(t, 1, recorded.Attempt)
+	})
+
+	t.Run("server error is retried", func(t *testing.T) {
+		queue, jobRepo, executionRepo := newTestQueue()
+		executionRepo.On("AddReaction", 1, mock.AnythingOfType("domain.ReactionExecution")).Return(nil)
+		jobRepo.On("Retry", job, mock.AnythingOfType("time.Duration"), "status 503").Return(nil)
+
+		queue.process(job, func(domain.ReactionJob) (domain.ReactionExecution, error) {
+			return domain.ReactionExecution{StatusCode: 503, Error: "status 503"}, errors.New("status 503")
+		})
+
+		jobRepo.AssertExpectations(t)
+		jobRepo.AssertNotCalled(t, "Bury", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("client error is buried at once", func(t *testing.T) {
+		queue, jobRepo, executionRepo := newTestQueue()
+		executionRepo.On("AddReaction", 1, mock.AnythingOfType("domain.ReactionExecution")).Return(nil)
+		jobRepo.On("Bury", job, "status 400", 400).Return(nil)
+
+		queue.process(job, func(domain.ReactionJob) (domain.ReactionExecution, error) {
+			return domain.ReactionExecution{StatusCode: 400, Error: "status 400"}, errors.New("status 400")
+		})
+
+		jobRepo.AssertExpectations(t)
+	})
+
+	t.Run("last attempt is buried", func(t *testing.T) {
+		queue, jobRepo, executionRepo := newTestQueue()
+		last := job
+		last.Attempts = 3
+		executionRepo.On("AddReaction", 1, mock.AnythingOfType("domain.ReactionExecution")).Return(nil)
+		jobRepo.On("Bury", last, "status 500", 500).Return(nil)
+
+		queue.process(last, func(domain.ReactionJob) (domain.ReactionExecution, error) {
+			return domain.ReactionExecution{StatusCode: 500, Error: "status 500"}, errors.New("status 500")
+		})
+
+		jobRepo.AssertExpectations(t)
+	})
+
+	t.Run("panic is buried", func(t *testing.T) {
+		queue, jobRepo, executionRepo := newTestQueue()
+		executionRepo.On("AddReaction", 1, mock.AnythingOfType("domain.ReactionExecution")).Return(nil)
+		jobRepo.On("Bury", job, "panic: boom", 0).Return(nil)
+
+		queue.process(job, func(domain.ReactionJob) (domain.ReactionExecution, error) { panic("boom") })
+
+		jobRepo.AssertExpectations(t)
+		recorded := executionRepo.Calls[0].Arguments.Get(1).(domain.ReactionExecution)
+		assert.Equal(t, 10, recorded.ReactionID)
+		assert.Equal(t, "panic: boom", recorded.Error)
+	})
+
+	t.Run("abandoned after too many attempts", func(t *testing.T) {
+		queue, jobRepo, executionRepo := newTestQueue()
+		stale := job
+		stale.Attempts = stale.MaxAttempts + 1
+		executionRepo.On("AddReaction", 1, mock.AnythingOfType("domain.ReactionExecution")).Return(nil)
+		jobRepo.On("Bury", stale, "abandoned after 3 attempts", 0).Return(nil)
+
+		ran := false
+		queue.process(stale, func(domain.ReactionJob) (domain.ReactionExecution, error) {
+			ran = true
+			return domain.ReactionExecution{StatusCode: 200}, nil
+		})
+
+		assert.False(t, ran)
+		jobRepo.AssertExpectations(t)
+		jobRepo.AssertNotCalled(t, "Complete", mock.Anything)
+	})
+}
+
+func TestReactionQueue_RetryDelay(t *testing.T) {
+	queue, _, _ := newTestQueue()
+
+	testCases := []struct {
+		attempt int
+		base    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{50, 10 * time.Second},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("attempt %d", tc.attempt), func(t *testing.T) {
+			delay := queue.retryDelay(tc.attempt)
+			assert.GreaterOrEqual(t, delay, tc.base)
+			assert.LessOrEqual(t, delay, tc.base+tc.base/5)
+		})
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	networkErr := fmt.Errorf("failed to call reaction endpoint: %w", &url.Error{Op: "Post", URL: "http://x", Err: errors.New("connection refused")})
+
+	testCases := []struct {
+		name     string
+		result   domain.ReactionExecution
+		err      error
+		expected bool
+	}{
+		{"success", domain.ReactionExecution{StatusCode: 200}, nil, false},
+		{"rate limited", domain.ReactionExecution{StatusCode: 429}, errors.New("status 429"), true},
+		{"server error", domain.ReactionExecution{StatusCode: 502}, errors.New("status 502"), true},
+		{"client error", domain.ReactionExecution{StatusCode: 404}, errors.New("status 404"), false},
+		{"network error", domain.ReactionExecution{}, networkErr, true},
+		{"marked retryable", domain.ReactionExecution{}, fmt.Errorf("%w: area lookup", ErrRetryable), true},
+		{"invalid payload", domain.ReactionExecution{}, errors.New("failed to marshal event payload"), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Retryable(tc.result, tc.err))
+		})
+	}
+}
+
+func TestReactionQueue_Replay(t *testing.T) {
+	letter := &domain.DeadLetter{ID: 9, ExecutionID: 1, UserID: 42, ReactionID: 10}
+
+	t.Run("owner replays", func(t *testing.T) {
+		queue, jobRepo, _ := newTestQueue()
+		jobRepo.On("GetDeadLetter", 9).Return(letter, nil)
+		jobRepo.On("Replay", *letter, 3).Return(domain.ReactionJob{ID: 20, ExecutionID: 1}, nil)
+
+		job, err := queue.Replay(42, 9)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 20, job.ID)
+		jobRepo.AssertExpectations(t)
+	})
+
+	t.Run("other user gets not found", func(t *testing.T) {
+		queue, jobRepo, _ := newTestQueue()
+		jobRepo.On("GetDeadLetter", 9).Return(letter, nil)
+
+		_, err := queue.Replay(7, 9)
+
+		assert.ErrorIs(t, err, ErrDeadLetterNotFound)
+		jobRepo.AssertNotCalled(t, "Replay", mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing letter", func(t *testing.T) {
+		queue, jobRepo, _ := newTestQueue()
+		jobRepo.On("GetDeadLetter", 9).Return(nil, nil)
+
+		_, err := queue.Replay(42, 9)
+
+		assert.ErrorIs(t, err, ErrDeadLetterNotFound)
+	})
+}
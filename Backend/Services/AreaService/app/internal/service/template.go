@@ -0,0 +1,58 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/raphael-guer1n/AREA/Shared/template"
+)
+
+var placeholderRegexp = regexp.MustCompile(`\{\{\s*([^}]+?)\s*\}\}`)
+
+// RenderTemplate replaces the {{key | filter:arg}} placeholders of input whose
+// key lookup knows. The others are kept for a later pass or, when final is
+// set, take the value of their default filter if they have one. On the final
+// pass an invalid placeholder is an error rather than being sent as is.
+func RenderTemplate(input string, lookup func(key string) (string, bool), final bool) (string, error) {
+	var renderErr error
+	result := placeholderRegexp.ReplaceAllStringFunc(input, func(match string) string {
+		if renderErr != nil {
+			return match
+		}
+		expression, err := template.Parse(placeholderRegexp.FindStringSubmatch(match)[1])
+		if err != nil {
+			if final {
+				renderErr = fmt.Errorf("placeholder %s: %w", match, err)
+			}
+			return match
+		}
+		value, found := lookup(expression.Key)
+		if !found && !final {
+			return match
+		}
+		rendered, found, err := expression.Apply(value, found)
+		if err != nil {
+			renderErr = err
+			return match
+		}
+		if !found {
+			return match
+		}
+		return template.String(rendered)
+	})
+	if renderErr != nil {
+		return "", renderErr
+	}
+	return result, nil
+}
+
+// ValidateTemplate checks the filters of the placeholders of a reaction input
+// when the area is saved.
+func ValidateTemplate(input string) error {
+	for _, match := range placeholderRegexp.FindAllStringSubmatch(input, -1) {
+		if _, err := template.Parse(match[1]); err != nil {
+			return fmt.Errorf("placeholder %s: %w", match[0], err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/raphael-guer1n/AREA/Shared/template"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderTemplate_Filters(t *testing.T) {
+	values := map[string]string{
+		"title":       "Release v2.0",
+		"description": "<p>Fixes &amp; improvements</p>",
+		"pub_date":    "Mon, 02 Jan 2006 15:04:05 +0000",
+		"timestamp":   "1136214245",
+		"query":       "a b&c",
+		"empty":       "",
+	}
+	lookup := func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"{{title}}", "Release v2.0"},
+		{"{{ title | upper }}", "RELEASE V2.0"},
+		{"{{title|lower}}", "release v2.0"},
+		{"{{title | truncate:7}}", "Rele..."},
+		{"{{title | truncate:7,\"\"}}", "Release"},
+		{"{{title | truncate:100}}", "Release v2.0"},
+		{"{{title | replace:\"v\",\"version \"}}", "Release version 2.0"},
+		{"{{description | html_strip}}", "Fixes & improvements"},
+		{"{{pub_date | date}}", "2006-01-02T15:04:05Z"},
+		{"{{pub_date | date:\"02/01/2006 15:04\",\"Europe/Paris\"}}", "02/01/2006 16:04"},
+		{"{{timestamp | date:date}}", "2006-01-02"},
+		{"{{pub_date | date:unix}}", "1136214245"},
+		{"{{title | json}}", `"Release v2.0"`},
+		{"q={{query | urlencode}}", "q=a+b%26c"},
+		{"{{empty | default:'none'}}", "none"},
+		{"{{missing | upper | default:\"n/a\"}}", "n/a"},
+		{"{{missing | default:\"n/a\" | upper}}", "N/A"},
+		{"{{title | upper}} by {{author}}", "RELEASE V2.0 by {{author}}"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			rendered, err := RenderTemplate(tc.input, lookup, true)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, rendered)
+		})
+	}
+}
+
+func TestRenderTemplate_KeepsUnknownKeysForLaterPass(t *testing.T) {
+	lookup := func(key string) (string, bool) {
+		if key == "title" {
+			return "hello", true
+		}
+		return "", false
+	}
+
+	rendered, err := RenderTemplate(`{{title | upper}} {{env.TOKEN}} {{location | default:"home"}}`, lookup, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `HELLO {{env.TOKEN}} {{location | default:"home"}}`, rendered)
+}
+
+func TestRenderTemplate_FilterError(t *testing.T) {
+	lookup := func(key string) (string, bool) {
+		return "not a date", true
+	}
+
+	_, err := RenderTemplate("{{pub_date | date}}", lookup, true)
+
+	assert.Error(t, err)
+}
+
+func TestRenderTemplate_InvalidPlaceholder(t *testing.T) {
+	lookup := func(key string) (string, bool) {
+		return "hello", true
+	}
+
+	rendered, err := RenderTemplate("{{title | upperr}}", lookup, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "{{title | upperr}}", rendered)
+
+	_, err = RenderTemplate("{{title | upperr}}", lookup, true)
+	assert.ErrorIs(t, err, template.ErrInvalid)
+}
+
+func TestRenderTemplate_TruncateCountsCharacters(t *testing.T) {
+	lookup := func(key string) (string, bool) {
+		return strings.Repeat("é", 2500), true
+	}
+
+	rendered, err := RenderTemplate("{{content | truncate:2000}}", lookup, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2000, len([]rune(rendered)))
+	assert.True(t, strings.HasSuffix(rendered, "..."))
+}
+
+func TestValidateTemplate(t *testing.T) {
+	testCases := []struct {
+		input string
+		valid bool
+	}{
+		{"plain text", true},
+		{"{{title}} {{ description | html_strip | truncate:2000 }}", true},
+		{`{{pub_date | date:"15:04","Europe/Paris"}}`, true},
+		{"{{title | shout}}", false},
+		{"{{title | truncate}}", false},
+		{"{{title | truncate:many}}", false},
+		{"{{title | upper:1}}", false},
+		{`{{pub_date | date:"15:04","Mars/Olympus"}}`, false},
+		{`{{title | default:"open}}`, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			err := ValidateTemplate(tc.input)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, template.ErrInvalid)
+			}
+		})
+	}
+}
@@ -13,11 +13,17 @@ CREATE TABLE IF NOT EXISTS reactions (
     provider TEXT NOT NULL,
     service TEXT NOT NULL,
     title TEXT NOT NULL,
-    inputs JSONB NOT NULL
+    inputs JSONB NOT NULL,
+    condition JSONB,
+    position INTEGER NOT NULL DEFAULT 0
 );
 
 CREATE INDEX IF NOT EXISTS reactions_area_id_idx ON reactions (area_id);
 
+-- Columns added after the table was created, for existing databases.
+ALTER TABLE reactions ADD COLUMN IF NOT EXISTS condition JSONB;
+ALTER TABLE reactions ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0;
+
 CREATE TABLE IF NOT EXISTS actions (
     id SERIAL PRIMARY KEY,
     area_id SERIAL NOT NULL REFERENCES areas(id) ON DELETE CASCADE,
@@ -26,4 +32,94 @@ CREATE TABLE IF NOT EXISTS actions (
     title TEXT NOT NULL,
     inputs JSONB NOT NULL,
     type TEXT NOT NULL
-)
\ No newline at end of file
+);
+
+CREATE TABLE IF NOT EXISTS executions (
+    id SERIAL PRIMARY KEY,
+    area_id INTEGER NOT NULL REFERENCES areas(id) ON DELETE CASCADE,
+    action_id INTEGER NOT NULL,
+    request_id TEXT NOT NULL,
+    output_fields JSONB NOT NULL,
+    status TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    duration_ms BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS executions_area_id_started_at_idx ON executions (area_id, started_at DESC);
+
+CREATE TABLE IF NOT EXISTS reaction_executions (
+    id SERIAL PRIMARY KEY,
+    execution_id INTEGER NOT NULL REFERENCES executions(id) ON DELETE CASCADE,
+    reaction_id INTEGER NOT NULL,
+    attempt INTEGER NOT NULL DEFAULT 1,
+    skipped BOOLEAN NOT NULL DEFAULT FALSE,
+    method TEXT NOT NULL DEFAULT '',
+    url TEXT NOT NULL DEFAULT '',
+    request_body TEXT NOT NULL DEFAULT '',
+    status_code INTEGER NOT NULL DEFAULT 0,
+    response_snippet TEXT NOT NULL DEFAULT '',
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    outputs JSONB
+);
+
+CREATE INDEX IF NOT EXISTS reaction_executions_execution_id_idx ON reaction_executions (execution_id);
+
+CREATE TABLE IF NOT EXISTS reaction_jobs (
+    id SERIAL PRIMARY KEY,
+    execution_id INTEGER NOT NULL REFERENCES executions(id) ON DELETE CASCADE,
+    area_id INTEGER NOT NULL,
+    reaction_id INTEGER NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    chained BOOLEAN NOT NULL DEFAULT FALSE,
+    user_id INTEGER NOT NULL,
+    request_id TEXT NOT NULL,
+    output_fields JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    next_run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_at TIMESTAMPTZ,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS reaction_jobs_due_idx ON reaction_jobs (status, next_run_at);
+CREATE INDEX IF NOT EXISTS reaction_jobs_execution_id_idx ON reaction_jobs (execution_id);
+
+CREATE TABLE IF NOT EXISTS reaction_dead_letters (
+    id SERIAL PRIMARY KEY,
+    execution_id INTEGER NOT NULL REFERENCES executions(id) ON DELETE CASCADE,
+    area_id INTEGER NOT NULL,
+    reaction_id INTEGER NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    chained BOOLEAN NOT NULL DEFAULT FALSE,
+    user_id INTEGER NOT NULL,
+    request_id TEXT NOT NULL,
+    output_fields JSONB NOT NULL,
+    attempts INTEGER NOT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    last_status_code INTEGER NOT NULL DEFAULT 0,
+    failed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS reaction_dead_letters_user_id_idx ON reaction_dead_letters (user_id, failed_at DESC);
+CREATE INDEX IF NOT EXISTS reaction_dead_letters_execution_id_idx ON reaction_dead_letters (execution_id);
+
+CREATE TABLE IF NOT EXISTS delayed_triggers (
+    id SERIAL PRIMARY KEY,
+    kind TEXT NOT NULL,
+    action_id INTEGER REFERENCES actions(id) ON DELETE CASCADE,
+    payload JSONB NOT NULL,
+    due_at TIMESTAMPTZ NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    locked_at TIMESTAMPTZ,
+    last_error TEXT NOT NULL DEFAULT '',
+    failed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS delayed_triggers_due_idx ON delayed_triggers (status, due_at);
+CREATE INDEX IF NOT EXISTS delayed_triggers_action_id_idx ON delayed_triggers (action_id);
@@ -22,10 +22,15 @@ func main() {
 	userProfileRepo := repository.NewUserProfileRepository(dbConn)
 	userFieldRepo := repository.NewUserServiceFieldRepository(dbConn)
 	userRepo := repository.NewUserRepository(dbConn)
+	apiKeyRepo := repository.NewAPIKeyRepository(dbConn)
+	revokedTokenRepo := repository.NewRevokedTokenRepository(dbConn)
 
 	// Build services
 	oauth2StorageSvc := service.NewOAuth2StorageService(userProfileRepo, userFieldRepo, cfg.ServiceServiceURL, cfg.InternalSecret)
 	authSvc := service.NewAuthService(userRepo)
+	apiKeySvc := service.NewAPIKeyService(apiKeyRepo, userRepo)
+	revocationSvc := service.NewTokenRevocationService(revokedTokenRepo)
+	go revocationSvc.StartCleanup(context.Background(), time.Hour)
 
 	// Initialize OAuth2 manager with service-service URL (lazy loading)
 	oauth2Manager := oauth2.NewManager(cfg.ServiceServiceURL, cfg.InternalSecret)
@@ -41,10 +46,11 @@ func main() {
 
 	// Build handlers
 	oauth2Handler := httphandler.NewOAuth2Handler(oauth2StorageSvc, oauth2Manager, authSvc, cfg)
-	authHandler := httphandler.NewAuthHandler(authSvc)
+	authHandler := httphandler.NewAuthHandler(authSvc, revocationSvc)
+	apiKeyHandler := httphandler.NewAPIKeyHandler(apiKeySvc)
 
 	// Build router
-	router := httphandler.NewRouter(authHandler, oauth2Handler)
+	router := httphandler.NewRouter(authHandler, oauth2Handler, apiKeyHandler)
 
 	addr := ":" + cfg.HTTPPort
 	log.Printf("Starting server on %s", addr)
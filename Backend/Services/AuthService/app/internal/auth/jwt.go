@@ -1,9 +1,14 @@
 package auth
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -11,6 +16,9 @@ import (
 
 var jwtSecret = []byte(getJWTSecret())
 
+// TokenTTL is the lifetime of the tokens issued by GenerateToken.
+const TokenTTL = 24 * time.Hour
+
 func getJWTSecret() string {
 	secret := os.Getenv("JWT_SECRET")
 	if secret == "" {
@@ -20,20 +28,69 @@ func getJWTSecret() string {
 }
 
 type Claims struct {
-	UserID int `json:"user_id"`
-	Sub string `json:"sub"`
+	UserID int    `json:"user_id"`
+	Sub    string `json:"sub"`
+	// IssuedAt is the `iat` claim, kept to the millisecond so that a token
+	// issued right after the revocation of its user is not covered by it. It
+	// replaces RegisteredClaims.IssuedAt, which jwt truncates to the second.
+	IssuedAt *MillisDate `json:"iat,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken creates a JWT token for a user
+// GetIssuedAt implements jwt.Claims with the millisecond `iat`.
+func (c Claims) GetIssuedAt() (*jwt.NumericDate, error) {
+	if c.IssuedAt == nil {
+		return nil, nil
+	}
+	return &jwt.NumericDate{Time: c.IssuedAt.Time}, nil
+}
+
+// MillisDate is a JWT numeric date, in seconds with 3 decimals.
+type MillisDate struct {
+	time.Time
+}
+
+func (d MillisDate) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(float64(d.UnixMilli())/1000, 'f', 3, 64)), nil
+}
+
+func (d *MillisDate) UnmarshalJSON(b []byte) error {
+	var seconds json.Number
+	if err := json.Unmarshal(b, &seconds); err != nil {
+		return err
+	}
+	f, err := seconds.Float64()
+	if err != nil {
+		return err
+	}
+	d.Time = time.UnixMilli(int64(math.Round(f * 1000)))
+	return nil
+}
+
+func newTokenID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// GenerateToken creates a JWT token for a user. The `jti` claim identifies the
+// token so that it can be revoked on its own.
 func GenerateToken(userID int) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", fmt.Errorf("error generating token id: %w", err)
+	}
+
 	claims := Claims{
 		Sub:    fmt.Sprintf("%d", userID),
 		UserID: userID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(TokenTTL)),
 		},
+		IssuedAt: &MillisDate{Time: time.Now()},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -42,6 +99,15 @@ func GenerateToken(userID int) (string, error) {
 
 // ValidateToken validates a JWT token and returns the user ID
 func ValidateToken(tokenString string) (int, error) {
+	claims, err := ParseToken(tokenString)
+	if err != nil {
+		return 0, err
+	}
+	return claims.UserID, nil
+}
+
+// ParseToken validates a JWT token and returns its claims
+func ParseToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("invalid signing method")
@@ -50,12 +116,12 @@ func ValidateToken(tokenString string) (int, error) {
 	})
 
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims.UserID, nil
+		return claims, nil
 	}
 
-	return 0, errors.New("invalid token")
+	return nil, errors.New("invalid token")
 }
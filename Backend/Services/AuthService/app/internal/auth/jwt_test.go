@@ -1,7 +1,10 @@
 package auth
 
 import (
+	"encoding/json"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -46,6 +49,21 @@ func TestGenerateToken_ValidClaims(t *testing.T) {
 	assert.Less(t, timeDiff.Abs().Seconds(), 5.0, "Expiry time should be within 5 seconds of 24 hours from now")
 }
 
+func TestGenerateToken_UniqueTokenID(t *testing.T) {
+	first, err := GenerateToken(1)
+	assert.NoError(t, err)
+	second, err := GenerateToken(1)
+	assert.NoError(t, err)
+
+	firstClaims, err := ParseToken(first)
+	assert.NoError(t, err)
+	secondClaims, err := ParseToken(second)
+	assert.NoError(t, err)
+
+	assert.Len(t, firstClaims.ID, 32)
+	assert.NotEqual(t, firstClaims.ID, secondClaims.ID)
+}
+
 func TestValidateToken_Success(t *testing.T) {
 	userID := 789
 
@@ -179,3 +197,26 @@ func TestValidateToken_MalformedToken(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateToken_IssuedAtMilliseconds(t *testing.T) {
+	before := time.Now().UnixMilli()
+
+	tokenString, err := GenerateToken(1)
+	assert.NoError(t, err)
+
+	token, _, err := jwt.NewParser(jwt.WithJSONNumber()).ParseUnverified(tokenString, jwt.MapClaims{})
+	assert.NoError(t, err)
+	iat := token.Claims.(jwt.MapClaims)["iat"].(json.Number).String()
+
+	seconds, millis, ok := strings.Cut(iat, ".")
+	assert.True(t, ok, "iat %s has no fractional part", iat)
+	assert.Len(t, millis, 3)
+	issuedAt, err := strconv.ParseInt(seconds+millis, 10, 64)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, issuedAt, before)
+	assert.Less(t, issuedAt, before+1000)
+
+	claims, err := ParseToken(tokenString)
+	assert.NoError(t, err)
+	assert.Equal(t, issuedAt, claims.IssuedAt.UnixMilli())
+}
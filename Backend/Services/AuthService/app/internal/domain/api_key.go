@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// APIKeyScopes is the catalog of scopes a key may carry. A JWT session holds
+// all of them, so a key can only narrow what its owner is allowed to do. The
+// gateway accepts keys on the routes listing one of these scopes.
+var APIKeyScopes = []string{"areas:read", "areas:write"}
+
+type APIKey struct {
+	ID          int        `json:"id"`
+	UserID      int        `json:"user_id"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	KeyHash     string     `json:"-"` // Never expose in JSON
+	Permissions []string   `json:"permissions"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type APIKeyRepository interface {
+	Create(key APIKey) (*APIKey, error)
+	ListByUser(userID int) ([]APIKey, error)
+	FindByHash(keyHash string) (*APIKey, error)
+	Revoke(userID, id int) error
+	TouchLastUsed(id int) error
+}
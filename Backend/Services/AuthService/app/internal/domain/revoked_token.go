@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// RevokedToken is an entry of the token deny-list. It revokes either a single
+// token (JTI), or every token of the user issued before IssuedBefore, compared
+// to the millisecond. Entries
+// are kept until ExpiresAt, after which the tokens they cover have expired.
+type RevokedToken struct {
+	ID           int        `json:"id"`
+	JTI          string     `json:"jti,omitempty"`
+	UserID       int        `json:"user_id"`
+	IssuedBefore *time.Time `json:"issued_before,omitempty"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	RevokedAt    time.Time  `json:"revoked_at"`
+}
+
+type RevokedTokenRepository interface {
+	Create(token RevokedToken) error
+	ListActiveSince(since time.Time) ([]RevokedToken, error)
+	DeleteExpired() (int64, error)
+}
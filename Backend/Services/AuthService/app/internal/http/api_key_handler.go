@@ -0,0 +1,227 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/raphael-guer1n/AREA/AuthService/internal/service"
+)
+
+type APIKeyHandler struct {
+	apiKeySvc *service.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeySvc *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeySvc: apiKeySvc,
+	}
+}
+
+// GET|POST /auth/api-keys - requires JWT authentication
+func (h *APIKeyHandler) handleAPIKeys(w http.ResponseWriter, req *http.Request) {
+	if isAPIKeyRequest(req) {
+		respondAPIKeyNotAllowed(w)
+		return
+	}
+	if req.Method == http.MethodGet {
+		h.handleListAPIKeys(w, req)
+		return
+	}
+	if req.Method == http.MethodPost {
+		h.handleCreateAPIKey(w, req)
+		return
+	}
+
+	respondJSON(w, http.StatusMethodNotAllowed, map[string]any{
+		"success": false,
+		"error":   "method not allowed",
+	})
+}
+
+func (h *APIKeyHandler) handleCreateAPIKey(w http.ResponseWriter, req *http.Request) {
+	userID, err := getUserIDFromRequest(req)
+	if err != nil {
+		respondJSON(w, http.StatusUnauthorized, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	var body struct {
+		Name          string   `json:"name"`
+		Permissions   []string `json:"permissions"`
+		ExpiresInDays int      `json:"expires_in_days"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]any{
+			"success": false,
+			"error":   "invalid request body",
+		})
+		return
+	}
+
+	rawKey, key, err := h.apiKeySvc.Issue(userID, body.Name, body.Permissions, body.ExpiresInDays)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrInvalidAPIKeyName),
+			errors.Is(err, service.ErrInvalidAPIKeyTTL),
+			errors.Is(err, service.ErrInvalidPermission):
+			status = http.StatusBadRequest
+		}
+		respondJSON(w, status, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]any{
+		"success": true,
+		"data": map[string]any{
+			"key":     rawKey,
+			"api_key": key,
+		},
+	})
+}
+
+func (h *APIKeyHandler) handleListAPIKeys(w http.ResponseWriter, req *http.Request) {
+	userID, err := getUserIDFromRequest(req)
+	if err != nil {
+		respondJSON(w, http.StatusUnauthorized, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	keys, err := h.apiKeySvc.List(userID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]any{
+		"success": true,
+		"data": map[string]any{
+			"api_keys": keys,
+		},
+	})
+}
+
+// DELETE /auth/api-keys/{id} - requires JWT authentication
+func (h *APIKeyHandler) handleRevokeAPIKey(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodDelete {
+		respondJSON(w, http.StatusMethodNotAllowed, map[string]any{
+			"success": false,
+			"error":   "method not allowed",
+		})
+		return
+	}
+
+	if isAPIKeyRequest(req) {
+		respondAPIKeyNotAllowed(w)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(req)
+	if err != nil {
+		respondJSON(w, http.StatusUnauthorized, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(req.URL.Path, "/auth/api-keys/"))
+	if err != nil || id <= 0 {
+		respondJSON(w, http.StatusBadRequest, map[string]any{
+			"success": false,
+			"error":   "invalid api key id",
+		})
+		return
+	}
+
+	if err := h.apiKeySvc.Revoke(userID, id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			status = http.StatusNotFound
+		}
+		respondJSON(w, status, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]any{
+		"success": true,
+		"message": "api key revoked",
+	})
+}
+
+// A key must not mint, list or revoke keys: it would outlive its own expiry
+// and scopes through the keys it creates.
+func respondAPIKeyNotAllowed(w http.ResponseWriter) {
+	respondJSON(w, http.StatusForbidden, map[string]any{
+		"success": false,
+		"error":   errAPIKeyNotAllowed.Error(),
+	})
+}
+
+// POST /auth/api-keys/verify - internal only, used by the gateway
+func (h *APIKeyHandler) handleVerifyAPIKey(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		respondJSON(w, http.StatusMethodNotAllowed, map[string]any{
+			"success": false,
+			"error":   "method not allowed",
+		})
+		return
+	}
+
+	var body struct {
+		Key string `json:"key"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]any{
+			"success": false,
+			"error":   "invalid request body",
+		})
+		return
+	}
+
+	key, user, err := h.apiKeySvc.Verify(body.Key)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrInvalidAPIKey),
+			errors.Is(err, service.ErrAPIKeyExpired),
+			errors.Is(err, service.ErrAPIKeyRevoked),
+			errors.Is(err, service.ErrAPIKeyOwnerNotFound):
+			status = http.StatusUnauthorized
+		}
+		respondJSON(w, status, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]any{
+		"success": true,
+		"data": map[string]any{
+			"user_id":     user.ID,
+			"email":       user.Email,
+			"key_id":      key.ID,
+			"permissions": key.Permissions,
+			"expires_at":  key.ExpiresAt,
+		},
+	})
+}
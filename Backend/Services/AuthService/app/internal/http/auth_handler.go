@@ -3,18 +3,23 @@ package http
 import (
 	"encoding/json"
 	"errors"
+	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/raphael-guer1n/AREA/AuthService/internal/service"
 )
 
 type AuthHandler struct {
-	authSvc *service.AuthService
+	authSvc       *service.AuthService
+	revocationSvc *service.TokenRevocationService
 }
 
-func NewAuthHandler(authSvc *service.AuthService) *AuthHandler {
+func NewAuthHandler(authSvc *service.AuthService, revocationSvc *service.TokenRevocationService) *AuthHandler {
 	return &AuthHandler{
-		authSvc: authSvc,
+		authSvc:       authSvc,
+		revocationSvc: revocationSvc,
 	}
 }
 
@@ -188,8 +193,93 @@ func (r *AuthHandler) handleDeleteMe(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if err := r.revocationSvc.RevokeUser(userID); err != nil {
+		log.Printf("failed to revoke tokens of deleted user %d: %v", userID, err)
+	}
+
 	respondJSON(w, http.StatusOK, map[string]any{
 		"success": true,
 		"message": "user deleted successfully",
 	})
 }
+
+// POST /auth/logout - requires JWT authentication
+func (r *AuthHandler) handleLogout(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		respondJSON(w, http.StatusMethodNotAllowed, map[string]any{
+			"success": false,
+			"error":   "method not allowed",
+		})
+		return
+	}
+
+	token, err := getBearerToken(req)
+	if err != nil {
+		respondJSON(w, http.StatusUnauthorized, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if err := r.revocationSvc.Logout(token); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidToken) {
+			status = http.StatusUnauthorized
+		}
+		respondJSON(w, status, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]any{
+		"success": true,
+		"message": "logged out",
+	})
+}
+
+// GET /auth/revocations?since=<unix seconds> - internal, synced by the gateway
+func (r *AuthHandler) handleRevocations(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		respondJSON(w, http.StatusMethodNotAllowed, map[string]any{
+			"success": false,
+			"error":   "method not allowed",
+		})
+		return
+	}
+
+	since := time.Unix(0, 0)
+	if raw := req.URL.Query().Get("since"); raw != "" {
+		seconds, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || seconds < 0 {
+			respondJSON(w, http.StatusBadRequest, map[string]any{
+				"success": false,
+				"error":   "since must be a unix timestamp in seconds",
+			})
+			return
+		}
+		since = time.Unix(seconds, 0)
+	}
+
+	// read before listing, so that the next sync from this instant misses nothing
+	serverTime := time.Now().Unix()
+
+	revocations, err := r.revocationSvc.List(since)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]any{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]any{
+		"success": true,
+		"data": map[string]any{
+			"revocations": revocations,
+			"server_time": serverTime,
+		},
+	})
+}
@@ -13,20 +13,37 @@ var (
 	errMissingAuthorizationHeader = errors.New("missing authorization header")
 	errInvalidAuthorizationHeader = errors.New("invalid authorization header format")
 	errInvalidOrExpiredToken      = errors.New("invalid or expired token")
+	errAPIKeyNotAllowed           = errors.New("api keys cannot be used on this route")
 )
 
-func getUserIDFromRequest(req *http.Request) (int, error) {
+func getBearerToken(req *http.Request) (string, error) {
 	authHeader := req.Header.Get("Authorization")
 	if authHeader == "" {
-		return 0, errMissingAuthorizationHeader
+		return "", errMissingAuthorizationHeader
 	}
 
 	parts := strings.Split(authHeader, " ")
 	if len(parts) != 2 || parts[0] != "Bearer" {
-		return 0, errInvalidAuthorizationHeader
+		return "", errInvalidAuthorizationHeader
+	}
+
+	return parts[1], nil
+}
+
+// isAPIKeyRequest reports whether the request is authenticated with an API
+// key rather than a JWT.
+func isAPIKeyRequest(req *http.Request) bool {
+	scheme, _, _ := strings.Cut(req.Header.Get("Authorization"), " ")
+	return scheme == "ApiKey"
+}
+
+func getUserIDFromRequest(req *http.Request) (int, error) {
+	token, err := getBearerToken(req)
+	if err != nil {
+		return 0, err
 	}
 
-	userID, err := auth.ValidateToken(parts[1])
+	userID, err := auth.ValidateToken(token)
 	if err != nil {
 		return 0, errInvalidOrExpiredToken
 	}
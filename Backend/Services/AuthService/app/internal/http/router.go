@@ -9,13 +9,15 @@ type Router struct {
 	mux           *http.ServeMux
 	oauth2Handler *OAuth2Handler
 	authHandler   *AuthHandler
+	apiKeyHandler *APIKeyHandler
 }
 
-func NewRouter(handler *AuthHandler, auth2Handler *OAuth2Handler) *Router {
+func NewRouter(handler *AuthHandler, auth2Handler *OAuth2Handler, apiKeyHandler *APIKeyHandler) *Router {
 	r := &Router{
 		mux:           http.NewServeMux(),
 		oauth2Handler: auth2Handler,
 		authHandler:   handler,
+		apiKeyHandler: apiKeyHandler,
 	}
 
 	r.routes()
@@ -27,6 +29,13 @@ func (r *Router) routes() {
 	r.mux.HandleFunc("/auth/register", r.authHandler.handleRegister)
 	r.mux.HandleFunc("/auth/login", r.authHandler.handleLogin)
 	r.mux.HandleFunc("/auth/me", r.authHandler.handleMe)
+	r.mux.HandleFunc("/auth/logout", r.authHandler.handleLogout)
+	r.mux.HandleFunc("/auth/revocations", r.authHandler.handleRevocations)
+
+	// API key routes
+	r.mux.HandleFunc("/auth/api-keys", r.apiKeyHandler.handleAPIKeys)
+	r.mux.HandleFunc("/auth/api-keys/verify", r.apiKeyHandler.handleVerifyAPIKey)
+	r.mux.HandleFunc("/auth/api-keys/", r.apiKeyHandler.handleRevokeAPIKey)
 
 	// OAuth2 routes
 	r.mux.HandleFunc("/oauth2/providers", r.oauth2Handler.handleListProviders)
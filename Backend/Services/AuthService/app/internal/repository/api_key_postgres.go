@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/raphael-guer1n/AREA/AuthService/internal/domain"
+)
+
+type apiKeyRepository struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) domain.APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, permissions, expires_at, last_used_at, revoked_at, created_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIKey(row rowScanner) (*domain.APIKey, error) {
+	var k domain.APIKey
+	var permissions []byte
+	var lastUsedAt, revokedAt sql.NullTime
+	if err := row.Scan(
+		&k.ID,
+		&k.UserID,
+		&k.Name,
+		&k.Prefix,
+		&k.KeyHash,
+		&permissions,
+		&k.ExpiresAt,
+		&lastUsedAt,
+		&revokedAt,
+		&k.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(permissions, &k.Permissions); err != nil {
+		return nil, err
+	}
+	if k.Permissions == nil {
+		k.Permissions = []string{}
+	}
+	if lastUsedAt.Valid {
+		k.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		k.RevokedAt = &revokedAt.Time
+	}
+	return &k, nil
+}
+
+func (r *apiKeyRepository) Create(key domain.APIKey) (*domain.APIKey, error) {
+	permissions, err := json.Marshal(key.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	row := r.db.QueryRow(
+		`INSERT INTO api_keys (user_id, name, prefix, key_hash, permissions, expires_at)
+         VALUES ($1, $2, $3, $4, $5, $6)
+         RETURNING `+apiKeyColumns,
+		key.UserID, key.Name, key.Prefix, key.KeyHash, permissions, key.ExpiresAt,
+	)
+	return scanAPIKey(row)
+}
+
+func (r *apiKeyRepository) ListByUser(userID int) ([]domain.APIKey, error) {
+	rows, err := r.db.Query(
+		`SELECT `+apiKeyColumns+` FROM api_keys WHERE user_id = $1 ORDER BY created_at DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []domain.APIKey{}
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *k)
+	}
+	return keys, rows.Err()
+}
+
+func (r *apiKeyRepository) FindByHash(keyHash string) (*domain.APIKey, error) {
+	k, err := scanAPIKey(r.db.QueryRow(
+		`SELECT `+apiKeyColumns+` FROM api_keys WHERE key_hash = $1`,
+		keyHash,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return k, nil
+}
+
+func (r *apiKeyRepository) Revoke(userID, id int) error {
+	res, err := r.db.Exec(
+		`UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`,
+		id, userID,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) TouchLastUsed(id int) error {
+	_, err := r.db.Exec(`UPDATE api_keys SET last_used_at = NOW() WHERE id = $1`, id)
+	return err
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/AuthService/internal/domain"
+)
+
+type revokedTokenRepository struct {
+	db *sql.DB
+}
+
+func NewRevokedTokenRepository(db *sql.DB) domain.RevokedTokenRepository {
+	return &revokedTokenRepository{db: db}
+}
+
+func (r *revokedTokenRepository) Create(token domain.RevokedToken) error {
+	var jti sql.NullString
+	if token.JTI != "" {
+		jti = sql.NullString{String: token.JTI, Valid: true}
+	}
+	_, err := r.db.Exec(
+		`INSERT INTO revoked_tokens (jti, user_id, issued_before, expires_at)
+         VALUES ($1, $2, $3, $4)
+         ON CONFLICT (jti) DO NOTHING`,
+		jti, token.UserID, token.IssuedBefore, token.ExpiresAt,
+	)
+	return err
+}
+
+func (r *revokedTokenRepository) ListActiveSince(since time.Time) ([]domain.RevokedToken, error) {
+	rows, err := r.db.Query(
+		`SELECT id, jti, user_id, issued_before, expires_at, revoked_at
+         FROM revoked_tokens
+         WHERE expires_at > NOW() AND revoked_at > $1
+         ORDER BY revoked_at`,
+		since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []domain.RevokedToken{}
+	for rows.Next() {
+		var t domain.RevokedToken
+		var jti sql.NullString
+		var issuedBefore sql.NullTime
+		if err := rows.Scan(&t.ID, &jti, &t.UserID, &issuedBefore, &t.ExpiresAt, &t.RevokedAt); err != nil {
+			return nil, err
+		}
+		t.JTI = jti.String
+		if issuedBefore.Valid {
+			t.IssuedBefore = &issuedBefore.Time
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, rows.Err()
+}
+
+func (r *revokedTokenRepository) DeleteExpired() (int64, error) {
+	res, err := r.db.Exec(`DELETE FROM revoked_tokens WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
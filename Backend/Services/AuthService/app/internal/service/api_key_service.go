@@ -0,0 +1,164 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/AuthService/internal/domain"
+)
+
+const (
+	apiKeyPrefix        = "area_pat_"
+	apiKeyDisplayLength = len(apiKeyPrefix) + 6
+	defaultAPIKeyTTL    = 90
+	maxAPIKeyTTL        = 365
+)
+
+var (
+	ErrInvalidAPIKeyName   = errors.New("invalid api key name (must be 1-100 characters)")
+	ErrInvalidAPIKeyTTL    = fmt.Errorf("invalid expires_in_days (must be between 1 and %d)", maxAPIKeyTTL)
+	ErrInvalidPermission   = errors.New("invalid permission")
+	ErrAPIKeyNotFound      = errors.New("api key not found")
+	ErrInvalidAPIKey       = errors.New("invalid api key")
+	ErrAPIKeyExpired       = errors.New("api key expired")
+	ErrAPIKeyRevoked       = errors.New("api key revoked")
+	ErrAPIKeyOwnerNotFound = errors.New("api key owner not found")
+)
+
+type APIKeyService struct {
+	repo     domain.APIKeyRepository
+	userRepo domain.UserRepository
+}
+
+func NewAPIKeyService(repo domain.APIKeyRepository, userRepo domain.UserRepository) *APIKeyService {
+	return &APIKeyService{repo: repo, userRepo: userRepo}
+}
+
+// HashAPIKey returns the value stored at rest for a raw key. Keys carry 256
+// bits of randomness, so a plain SHA-256 is enough.
+func HashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateAPIKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Issue creates a key for the user with scopes from domain.APIKeyScopes. The
+// raw key is only returned here and cannot be recovered afterwards.
+func (s *APIKeyService) Issue(userID int, name string, permissions []string, expiresInDays int) (string, *domain.APIKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return "", nil, ErrInvalidAPIKeyName
+	}
+
+	if expiresInDays == 0 {
+		expiresInDays = defaultAPIKeyTTL
+	}
+	if expiresInDays < 0 || expiresInDays > maxAPIKeyTTL {
+		return "", nil, ErrInvalidAPIKeyTTL
+	}
+
+	if len(permissions) == 0 {
+		return "", nil, fmt.Errorf("%w: at least one scope is required", ErrInvalidPermission)
+	}
+	perms := make([]string, 0, len(permissions))
+	seen := make(map[string]bool, len(permissions))
+	for _, p := range permissions {
+		p = strings.TrimSpace(p)
+		if !slices.Contains(domain.APIKeyScopes, p) {
+			return "", nil, fmt.Errorf("%w: %q (must be one of %s)", ErrInvalidPermission, p, strings.Join(domain.APIKeyScopes, ", "))
+		}
+		if !seen[p] {
+			seen[p] = true
+			perms = append(perms, p)
+		}
+	}
+
+	rawKey, err := generateAPIKey()
+	if err != nil {
+		return "", nil, fmt.Errorf("error generating api key: %w", err)
+	}
+
+	key, err := s.repo.Create(domain.APIKey{
+		UserID:      userID,
+		Name:        name,
+		Prefix:      rawKey[:apiKeyDisplayLength],
+		KeyHash:     HashAPIKey(rawKey),
+		Permissions: perms,
+		ExpiresAt:   time.Now().Add(time.Duration(expiresInDays) * 24 * time.Hour),
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("error creating api key: %w", err)
+	}
+
+	return rawKey, key, nil
+}
+
+func (s *APIKeyService) List(userID int) ([]domain.APIKey, error) {
+	keys, err := s.repo.ListByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing api keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (s *APIKeyService) Revoke(userID, id int) error {
+	if err := s.repo.Revoke(userID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAPIKeyNotFound
+		}
+		return fmt.Errorf("error revoking api key: %w", err)
+	}
+	return nil
+}
+
+// Verify resolves a raw key to its key record and owner. It is called by the
+// gateway for `Authorization: ApiKey` requests.
+func (s *APIKeyService) Verify(rawKey string) (*domain.APIKey, *domain.User, error) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.repo.FindByHash(HashAPIKey(rawKey))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error finding api key: %w", err)
+	}
+	if key == nil {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	if key.RevokedAt != nil {
+		return nil, nil, ErrAPIKeyRevoked
+	}
+	if !key.ExpiresAt.After(time.Now()) {
+		return nil, nil, ErrAPIKeyExpired
+	}
+
+	user, err := s.userRepo.FindByID(key.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error finding user: %w", err)
+	}
+	if user == nil {
+		return nil, nil, ErrAPIKeyOwnerNotFound
+	}
+
+	if err := s.repo.TouchLastUsed(key.ID); err != nil {
+		log.Printf("failed to update last_used_at of api key %d: %v", key.ID, err)
+	}
+
+	return key, user, nil
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/AuthService/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAPIKeyRepository is a mock implementation of APIKeyRepository
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(key domain.APIKey) (*domain.APIKey, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) ListByUser(userID int) ([]domain.APIKey, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindByHash(keyHash string) (*domain.APIKey, error) {
+	args := m.Called(keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(userID, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func TestAPIKeyService_Issue_Success(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	svc := NewAPIKeyService(mockRepo, new(MockUserRepository))
+
+	var stored domain.APIKey
+	mockRepo.On("Create", mock.AnythingOfType("domain.APIKey")).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(domain.APIKey)
+	}).Return(&domain.APIKey{ID: 1, UserID: 7, Name: "ci"}, nil)
+
+	rawKey, key, err := svc.Issue(7, " ci ", []string{"areas:read", "areas:read", "areas:write"}, 0)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, key)
+	assert.True(t, strings.HasPrefix(rawKey, apiKeyPrefix))
+	assert.Equal(t, "ci", stored.Name)
+	assert.Equal(t, HashAPIKey(rawKey), stored.KeyHash)
+	assert.NotContains(t, stored.KeyHash, rawKey)
+	assert.Equal(t, rawKey[:apiKeyDisplayLength], stored.Prefix)
+	assert.Equal(t, []string{"areas:read", "areas:write"}, stored.Permissions)
+	assert.WithinDuration(t, time.Now().Add(defaultAPIKeyTTL*24*time.Hour), stored.ExpiresAt, time.Minute)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAPIKeyService_Issue_InvalidInput(t *testing.T) {
+	svc := NewAPIKeyService(new(MockAPIKeyRepository), new(MockUserRepository))
+
+	testCases := []struct {
+		name        string
+		keyName     string
+		permissions []string
+		ttl         int
+		expected    error
+	}{
+		{"empty name", "  ", nil, 30, ErrInvalidAPIKeyName},
+		{"ttl too long", "ci", nil, maxAPIKeyTTL + 1, ErrInvalidAPIKeyTTL},
+		{"negative ttl", "ci", nil, -1, ErrInvalidAPIKeyTTL},
+		{"empty permission", "ci", []string{""}, 30, ErrInvalidPermission},
+		{"permission with comma", "ci", []string{"a,b"}, 30, ErrInvalidPermission},
+		{"no scope", "ci", nil, 30, ErrInvalidPermission},
+		{"scope outside the catalog", "ci", []string{"areas:read", "admin"}, 30, ErrInvalidPermission},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rawKey, key, err := svc.Issue(1, tc.keyName, tc.permissions, tc.ttl)
+
+			assert.ErrorIs(t, err, tc.expected)
+			assert.Empty(t, rawKey)
+			assert.Nil(t, key)
+		})
+	}
+}
+
+func TestAPIKeyService_Revoke_NotFound(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	svc := NewAPIKeyService(mockRepo, new(MockUserRepository))
+
+	mockRepo.On("Revoke", 1, 42).Return(sql.ErrNoRows)
+
+	err := svc.Revoke(1, 42)
+
+	assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAPIKeyService_Verify_Success(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	mockUserRepo := new(MockUserRepository)
+	svc := NewAPIKeyService(mockRepo, mockUserRepo)
+
+	rawKey := apiKeyPrefix + "secret"
+	mockRepo.On("FindByHash", HashAPIKey(rawKey)).Return(&domain.APIKey{
+		ID:          3,
+		UserID:      7,
+		Permissions: []string{"areas:read"},
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}, nil)
+	mockRepo.On("TouchLastUsed", 3).Return(nil)
+	mockUserRepo.On("FindByID", 7).Return(&domain.User{ID: 7, Email: "ci@example.com"}, nil)
+
+	key, user, err := svc.Verify(rawKey)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, key.ID)
+	assert.Equal(t, "ci@example.com", user.Email)
+	mockRepo.AssertExpectations(t)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestAPIKeyService_Verify_Rejected(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Minute)
+
+	testCases := []struct {
+		name     string
+		stored   *domain.APIKey
+		expected error
+	}{
+		{"unknown key", nil, ErrInvalidAPIKey},
+		{"expired key", &domain.APIKey{ID: 1, ExpiresAt: time.Now().Add(-time.Hour)}, ErrAPIKeyExpired},
+		{"revoked key", &domain.APIKey{ID: 1, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}, ErrAPIKeyRevoked},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockAPIKeyRepository)
+			svc := NewAPIKeyService(mockRepo, new(MockUserRepository))
+
+			rawKey := apiKeyPrefix + "secret"
+			if tc.stored == nil {
+				mockRepo.On("FindByHash", HashAPIKey(rawKey)).Return(nil, nil)
+			} else {
+				mockRepo.On("FindByHash", HashAPIKey(rawKey)).Return(tc.stored, nil)
+			}
+
+			key, user, err := svc.Verify(rawKey)
+
+			assert.ErrorIs(t, err, tc.expected)
+			assert.Nil(t, key)
+			assert.Nil(t, user)
+		})
+	}
+}
+
+func TestAPIKeyService_Verify_WrongFormat(t *testing.T) {
+	svc := NewAPIKeyService(new(MockAPIKeyRepository), new(MockUserRepository))
+
+	_, _, err := svc.Verify("not-an-area-key")
+
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+}
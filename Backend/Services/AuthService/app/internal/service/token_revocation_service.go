@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/AuthService/internal/auth"
+	"github.com/raphael-guer1n/AREA/AuthService/internal/domain"
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// TokenRevocationService maintains the deny-list of revoked JWTs. The gateway
+// syncs it periodically and rejects the tokens it covers.
+type TokenRevocationService struct {
+	repo domain.RevokedTokenRepository
+}
+
+func NewTokenRevocationService(repo domain.RevokedTokenRepository) *TokenRevocationService {
+	return &TokenRevocationService{repo: repo}
+}
+
+// Logout revokes the given token until it expires. Tokens issued before the
+// `jti` claim existed cannot be told apart, so every token of the user issued
+// up to that one, included, is revoked instead.
+func (s *TokenRevocationService) Logout(tokenString string) error {
+	claims, err := auth.ParseToken(tokenString)
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	userID := claims.UserID
+	if userID == 0 && claims.Sub != "" {
+		if id, err := strconv.Atoi(claims.Sub); err == nil {
+			userID = id
+		}
+	}
+
+	entry := domain.RevokedToken{
+		JTI:    claims.ID,
+		UserID: userID,
+	}
+	if claims.ExpiresAt != nil {
+		entry.ExpiresAt = claims.ExpiresAt.Time
+	} else {
+		entry.ExpiresAt = time.Now().Add(auth.TokenTTL)
+	}
+	if entry.JTI == "" {
+		issuedBefore := time.Now()
+		if claims.IssuedAt != nil {
+			// the cutoff is exclusive
+			issuedBefore = claims.IssuedAt.Time.Add(time.Millisecond)
+		}
+		entry.IssuedBefore = &issuedBefore
+	}
+
+	if err := s.repo.Create(entry); err != nil {
+		return fmt.Errorf("error revoking token: %w", err)
+	}
+	return nil
+}
+
+// RevokeUser revokes every token issued to the user so far, e.g. when the
+// account is deleted.
+func (s *TokenRevocationService) RevokeUser(userID int) error {
+	now := time.Now()
+	err := s.repo.Create(domain.RevokedToken{
+		UserID:       userID,
+		IssuedBefore: &now,
+		ExpiresAt:    now.Add(auth.TokenTTL),
+	})
+	if err != nil {
+		return fmt.Errorf("error revoking user tokens: %w", err)
+	}
+	return nil
+}
+
+// List returns the entries still in force that were revoked after `since`.
+func (s *TokenRevocationService) List(since time.Time) ([]domain.RevokedToken, error) {
+	tokens, err := s.repo.ListActiveSince(since)
+	if err != nil {
+		return nil, fmt.Errorf("error listing revoked tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// StartCleanup drops the entries whose tokens have all expired.
+func (s *TokenRevocationService) StartCleanup(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.repo.DeleteExpired()
+			if err != nil {
+				log.Printf("failed to delete expired revoked tokens: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("deleted %d expired revoked tokens", deleted)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/raphael-guer1n/AREA/AuthService/internal/auth"
+	"github.com/raphael-guer1n/AREA/AuthService/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRevokedTokenRepository is a mock implementation of RevokedTokenRepository
+type MockRevokedTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRevokedTokenRepository) Create(token domain.RevokedToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockRevokedTokenRepository) ListActiveSince(since time.Time) ([]domain.RevokedToken, error) {
+	args := m.Called(since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.RevokedToken), args.Error(1)
+}
+
+func (m *MockRevokedTokenRepository) DeleteExpired() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestTokenRevocationService_Logout_RevokesTokenID(t *testing.T) {
+	mockRepo := new(MockRevokedTokenRepository)
+	svc := NewTokenRevocationService(mockRepo)
+
+	token, err := auth.GenerateToken(7)
+	assert.NoError(t, err)
+	claims, err := auth.ParseToken(token)
+	assert.NoError(t, err)
+
+	var stored domain.RevokedToken
+	mockRepo.On("Create", mock.AnythingOfType("domain.RevokedToken")).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(domain.RevokedToken)
+	}).Return(nil)
+
+	err = svc.Logout(token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, claims.ID, stored.JTI)
+	assert.Equal(t, 7, stored.UserID)
+	assert.Nil(t, stored.IssuedBefore)
+	assert.Equal(t, claims.ExpiresAt.Time, stored.ExpiresAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTokenRevocationService_Logout_TokenWithoutID(t *testing.T) {
+	mockRepo := new(MockRevokedTokenRepository)
+	svc := NewTokenRevocationService(mockRepo)
+
+	issuedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	legacy := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+		UserID: 7,
+		Sub:    "7",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(issuedAt.Add(auth.TokenTTL)),
+		},
+		IssuedAt: &auth.MillisDate{Time: issuedAt},
+	})
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		secret = "dev-secret-key-change-me"
+	}
+	token, err := legacy.SignedString([]byte(secret))
+	assert.NoError(t, err)
+
+	var stored domain.RevokedToken
+	mockRepo.On("Create", mock.AnythingOfType("domain.RevokedToken")).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(domain.RevokedToken)
+	}).Return(nil)
+
+	err = svc.Logout(token)
+
+	assert.NoError(t, err)
+	assert.Empty(t, stored.JTI)
+	if assert.NotNil(t, stored.IssuedBefore) {
+		assert.True(t, issuedAt.Add(time.Millisecond).Equal(*stored.IssuedBefore))
+	}
+}
+
+func TestTokenRevocationService_Logout_InvalidToken(t *testing.T) {
+	mockRepo := new(MockRevokedTokenRepository)
+	svc := NewTokenRevocationService(mockRepo)
+
+	err := svc.Logout("not.a.token")
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestTokenRevocationService_RevokeUser(t *testing.T) {
+	mockRepo := new(MockRevokedTokenRepository)
+	svc := NewTokenRevocationService(mockRepo)
+
+	var stored domain.RevokedToken
+	mockRepo.On("Create", mock.AnythingOfType("domain.RevokedToken")).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(domain.RevokedToken)
+	}).Return(nil)
+
+	err := svc.RevokeUser(42)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, stored.UserID)
+	assert.Empty(t, stored.JTI)
+	if assert.NotNil(t, stored.IssuedBefore) {
+		assert.WithinDuration(t, time.Now(), *stored.IssuedBefore, time.Minute)
+	}
+	assert.WithinDuration(t, time.Now().Add(auth.TokenTTL), stored.ExpiresAt, time.Minute)
+}
+
+func TestTokenRevocationService_List_RepositoryError(t *testing.T) {
+	mockRepo := new(MockRevokedTokenRepository)
+	svc := NewTokenRevocationService(mockRepo)
+
+	since := time.Unix(100, 0)
+	mockRepo.On("ListActiveSince", since).Return(nil, errors.New("database error"))
+
+	tokens, err := svc.List(since)
+
+	assert.Error(t, err)
+	assert.Nil(t, tokens)
+	mockRepo.AssertExpectations(t)
+}
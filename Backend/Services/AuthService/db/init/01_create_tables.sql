@@ -60,3 +60,33 @@ CREATE INDEX IF NOT EXISTS idx_user_service_fields_profile
 
 CREATE INDEX IF NOT EXISTS idx_user_service_fields_service_key_value
     ON user_service_fields(field_key, value_string, profile_id);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id           BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id      BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name         TEXT   NOT NULL,
+    prefix       TEXT   NOT NULL,
+    key_hash     TEXT   NOT NULL UNIQUE,
+    permissions  JSONB  NOT NULL DEFAULT '[]'::jsonb,
+    expires_at   TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id);
+
+-- no foreign key on user_id: entries must outlive the deleted accounts
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    id            BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    jti           TEXT UNIQUE,
+    user_id       BIGINT NOT NULL,
+    issued_before TIMESTAMPTZ,
+    expires_at    TIMESTAMPTZ NOT NULL,
+    revoked_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CHECK (jti IS NOT NULL OR issued_before IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_revoked_at ON revoked_tokens(revoked_at);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
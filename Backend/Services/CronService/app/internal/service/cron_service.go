@@ -258,7 +258,8 @@ func (s *CronService) unscheduleAction(actionID int) {
 }
 
 func (s *CronService) triggerAction(action *domain.Action) {
-	log.Printf("Triggering action %d", action.ActionID)
+	requestID := NewRequestID()
+	log.Printf("Triggering action %d (request_id=%s)", action.ActionID, requestID)
 
 	outputFields := s.buildOutputFields(action)
 
@@ -267,8 +268,8 @@ func (s *CronService) triggerAction(action *domain.Action) {
 		OutputFields: outputFields,
 	}
 
-	if err := s.callAreaService(triggerReq); err != nil {
-		log.Printf("Failed to trigger area service for action %d: %v", action.ActionID, err)
+	if err := s.callAreaService(requestID, triggerReq); err != nil {
+		log.Printf("Failed to trigger area service for action %d (request_id=%s): %v", action.ActionID, requestID, err)
 	}
 }
 
@@ -296,7 +297,7 @@ func (s *CronService) buildOutputFields(action *domain.Action) []domain.OutputFi
 	return outputFields
 }
 
-func (s *CronService) callAreaService(req domain.TriggerAreaRequest) error {
+func (s *CronService) callAreaService(requestID string, req domain.TriggerAreaRequest) error {
 	url := fmt.Sprintf("%s/triggerArea", s.areaServiceURL)
 
 	jsonData, err := json.Marshal(req)
@@ -311,6 +312,7 @@ func (s *CronService) callAreaService(req domain.TriggerAreaRequest) error {
 
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("X-Internal-Secret", s.internalSecret)
+	httpReq.Header.Set(RequestIDHeader, requestID)
 
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(httpReq)
@@ -319,10 +321,10 @@ func (s *CronService) callAreaService(req domain.TriggerAreaRequest) error {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return fmt.Errorf("area service returned status %d", resp.StatusCode)
 	}
 
-	log.Printf("Successfully triggered area service for action %d", req.ActionID)
+	log.Printf("Successfully triggered area service for action %d (request_id=%s)", req.ActionID, requestID)
 	return nil
 }
@@ -20,7 +20,7 @@ func main() {
 	oauth2TokenSvc := service.NewOAuth2TokenService(cfg.AuthServiceURL, cfg.InternalSecret)
 	requestSvc := service.NewRequestService(oauth2TokenSvc, cfg.LogProviderRequests)
 	subscriptionSvc := service.NewSubscriptionService(repo, providerConfigSvc, requestSvc)
-	authSvc := service.NewAuthService(cfg.AuthServiceURL)
+	authSvc := service.NewAuthService(cfg.AuthServiceURL, cfg.InternalSecret)
 	areaTriggerSvc := service.NewAreaTriggerService(cfg.AreaServiceURL, cfg.InternalSecret)
 	pollingWorker := service.NewPollingWorker(repo, providerConfigSvc, requestSvc, areaTriggerSvc, cfg.PollingTickSeconds)
 	go pollingWorker.Start()
@@ -2,7 +2,10 @@ module github.com/raphael-guer1n/AREA/PollingService
 
 go 1.22
 
-require github.com/lib/pq v1.10.9
+require (
+	github.com/lib/pq v1.10.9
+	github.com/raphael-guer1n/AREA/Shared v0.0.0
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
@@ -11,3 +14,5 @@ require (
 	github.com/stretchr/testify v1.11.1 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/raphael-guer1n/AREA/Shared => ../../../Shared
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/raphael-guer1n/AREA/PollingService/internal/service"
+	"github.com/raphael-guer1n/AREA/Shared/identity"
 )
 
 type ActionHandler struct {
@@ -481,21 +482,23 @@ func (h *ActionHandler) handleDeleteAction(w http.ResponseWriter, req *http.Requ
 }
 
 func (h *ActionHandler) resolveUser(req *http.Request) (int, error) {
-	authHeader := strings.TrimSpace(req.Header.Get("Authorization"))
-	if authHeader == "" {
-		return 0, errors.New("missing Authorization header")
-	}
-	if !strings.HasPrefix(authHeader, "Bearer ") {
-		return 0, errors.New("authorization must be Bearer <token>")
-	}
-	token := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
-	if token == "" {
-		return 0, errors.New("empty token")
-	}
 	if h.authSvc == nil {
 		return 0, errors.New("auth service not configured")
 	}
-	userID, err := h.authSvc.GetUserID(authHeader)
+	if req.Header.Get(identity.UserIDHeader) == "" {
+		authHeader := strings.TrimSpace(req.Header.Get("Authorization"))
+		if authHeader == "" {
+			return 0, errors.New("missing Authorization header")
+		}
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			return 0, errors.New("authorization must be Bearer <token>")
+		}
+		token := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
+		if token == "" {
+			return 0, errors.New("empty token")
+		}
+	}
+	userID, err := h.authSvc.ResolveUserID(req)
 	if err != nil {
 		return 0, err
 	}
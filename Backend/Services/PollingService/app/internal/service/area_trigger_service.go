@@ -10,6 +10,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/raphael-guer1n/AREA/PollingService/internal/utils"
 )
 
 type TriggerOutputField struct {
@@ -33,10 +35,11 @@ func NewAreaTriggerService(baseURL string, internalSecret string) *AreaTriggerSe
 	}
 }
 
-func (s *AreaTriggerService) Trigger(actionID int, outputFields []TriggerOutputField) error {
+func (s *AreaTriggerService) Trigger(requestID string, actionID int, outputFields []TriggerOutputField) error {
 	if actionID <= 0 {
 		return errors.New("action_id is required")
 	}
+	requestID = utils.RequestIDOrNew(requestID)
 	endpoint := s.baseURL + "/triggerArea"
 	payload := map[string]any{
 		"action_id":     actionID,
@@ -51,6 +54,7 @@ func (s *AreaTriggerService) Trigger(actionID int, outputFields []TriggerOutputF
 		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(utils.RequestIDHeader, requestID)
 	if s.internalSecret != "" {
 		req.Header.Set("X-Internal-Secret", s.internalSecret)
 	}
@@ -73,6 +77,6 @@ func (s *AreaTriggerService) Trigger(actionID int, outputFields []TriggerOutputF
 		return errors.New(message)
 	}
 
-	log.Printf("area trigger sent: action_id=%d status=%d", actionID, resp.StatusCode)
+	log.Printf("area trigger sent: action_id=%d status=%d request_id=%s", actionID, resp.StatusCode, requestID)
 	return nil
 }
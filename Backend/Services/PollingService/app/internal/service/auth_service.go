@@ -7,22 +7,39 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/raphael-guer1n/AREA/Shared/identity"
 )
 
 type AuthService struct {
-	baseURL string
-	client  *http.Client
+	baseURL        string
+	internalSecret string
+	client         *http.Client
 }
 
-func NewAuthService(baseURL string) *AuthService {
+func NewAuthService(baseURL, internalSecret string) *AuthService {
 	return &AuthService{
-		baseURL: strings.TrimRight(baseURL, "/"),
+		baseURL:        strings.TrimRight(baseURL, "/"),
+		internalSecret: internalSecret,
 		client: &http.Client{
 			Timeout: 5 * time.Second,
 		},
 	}
 }
 
+// ResolveUserID trusts the identity headers signed by the gateway and only
+// calls /auth/me for requests that did not carry them.
+func (s *AuthService) ResolveUserID(req *http.Request) (int, error) {
+	id, err := identity.FromRequest(req, s.internalSecret)
+	if err == nil {
+		return id.UserID, nil
+	}
+	if !errors.Is(err, identity.ErrNoIdentity) {
+		return 0, err
+	}
+	return s.GetUserID(req.Header.Get("Authorization"))
+}
+
 func (s *AuthService) GetUserID(authHeader string) (int, error) {
 	if strings.TrimSpace(authHeader) == "" {
 		return 0, errors.New("missing authorization header")
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/raphael-guer1n/AREA/Shared/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthService_ResolveUserID(t *testing.T) {
+	authCalls := 0
+	auth := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authCalls++
+		json.NewEncoder(w).Encode(map[string]any{"success": true, "data": map[string]any{"user": map[string]any{"id": 7}}})
+	}))
+	defer auth.Close()
+	svc := NewAuthService(auth.URL, "secret")
+
+	t.Run("signed identity is trusted", func(t *testing.T) {
+		authCalls = 0
+		req := httptest.NewRequest(http.MethodGet, "/subscriptions", nil)
+		identity.SetHeaders(req.Header, identity.Identity{UserID: 42}, time.Now(), "secret")
+
+		userID, err := svc.ResolveUserID(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 42, userID)
+		assert.Zero(t, authCalls)
+	})
+
+	t.Run("expired identity does not fall back to the token", func(t *testing.T) {
+		authCalls = 0
+		req := httptest.NewRequest(http.MethodGet, "/subscriptions", nil)
+		identity.SetHeaders(req.Header, identity.Identity{UserID: 42}, time.Now().Add(-time.Hour), "secret")
+		req.Header.Set("Authorization", "Bearer token")
+
+		_, err := svc.ResolveUserID(req)
+
+		assert.Error(t, err)
+		assert.Zero(t, authCalls)
+	})
+
+	t.Run("no identity asks AuthService", func(t *testing.T) {
+		authCalls = 0
+		req := httptest.NewRequest(http.MethodGet, "/subscriptions", nil)
+		req.Header.Set("Authorization", "Bearer token")
+
+		userID, err := svc.ResolveUserID(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, userID)
+		assert.Equal(t, 1, authCalls)
+	})
+}
@@ -10,5 +10,5 @@ type ProviderConfigServiceInterface interface {
 }
 
 type RequestServiceInterface interface {
-	ExecuteRequest(request config.PollingProviderRequestConfig, provider string, userID int, ctx utils.TemplateContext, queryOverrides map[string]string) ([]byte, error)
+	ExecuteRequest(requestID string, request config.PollingProviderRequestConfig, provider string, userID int, ctx utils.TemplateContext, queryOverrides map[string]string) ([]byte, error)
 }
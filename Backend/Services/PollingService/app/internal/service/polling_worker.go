@@ -96,7 +96,10 @@ func (w *PollingWorker) processSubscription(sub *domain.Subscription) error {
 		Env:      utils.EnvMap(),
 	}
 
-	payloadBody, err := w.requestSvc.ExecuteRequest(providerConfig.Request, sub.Provider, sub.UserID, ctx, nil)
+	// one id per poll, so the provider call and every trigger it causes share it
+	requestID := utils.NewRequestID()
+
+	payloadBody, err := w.requestSvc.ExecuteRequest(requestID, providerConfig.Request, sub.Provider, sub.UserID, ctx, nil)
 	if err != nil {
 		return w.finishWithError(sub, providerConfig, err)
 	}
@@ -166,12 +169,12 @@ func (w *PollingWorker) processSubscription(sub *domain.Subscription) error {
 				item := newItems[i]
 				mapped, err := buildMappings(item, source.Mappings, ctx)
 				if err != nil {
-					log.Printf("polling: mapping error action_id=%d provider=%s err=%v", sub.ActionID, sub.Service, err)
+					log.Printf("polling: mapping error action_id=%d provider=%s request_id=%s err=%v", sub.ActionID, sub.Service, requestID, err)
 					continue
 				}
 				outputFields := buildOutputFields(source.Mappings, mapped)
-				if err := w.areaTriggerSvc.Trigger(sub.ActionID, outputFields); err != nil {
-					log.Printf("polling: trigger failed action_id=%d provider=%s err=%v", sub.ActionID, sub.Service, err)
+				if err := w.areaTriggerSvc.Trigger(requestID, sub.ActionID, outputFields); err != nil {
+					log.Printf("polling: trigger failed action_id=%d provider=%s request_id=%s err=%v", sub.ActionID, sub.Service, requestID, err)
 				}
 			}
 		}
@@ -32,7 +32,9 @@ func NewRequestService(oauth2TokenSvc *OAuth2TokenService, logRequests bool) *Re
 	}
 }
 
-func (s *RequestService) ExecuteRequest(request config.PollingProviderRequestConfig, provider string, userID int, ctx utils.TemplateContext, queryOverrides map[string]string) ([]byte, error) {
+func (s *RequestService) ExecuteRequest(requestID string, request config.PollingProviderRequestConfig, provider string, userID int, ctx utils.TemplateContext, queryOverrides map[string]string) ([]byte, error) {
+	requestID = utils.RequestIDOrNew(requestID)
+
 	urlValue, err := utils.RenderTemplateString(request.URLTemplate, ctx)
 	if err != nil {
 		return nil, err
@@ -121,6 +123,7 @@ func (s *RequestService) ExecuteRequest(request config.PollingProviderRequestCon
 	if contentType != "" && req.Header.Get("Content-Type") == "" {
 		req.Header.Set("Content-Type", contentType)
 	}
+	req.Header.Set(utils.RequestIDHeader, requestID)
 
 	if request.Auth != nil {
 		switch request.Auth.Type {
@@ -144,7 +147,7 @@ func (s *RequestService) ExecuteRequest(request config.PollingProviderRequestCon
 	resp, err := s.client.Do(req)
 	if err != nil {
 		if s.logRequests {
-			log.Printf("polling: provider request failed provider=%s method=%s url=%s err=%v", provider, request.Method, urlStr, err)
+			log.Printf("polling: provider request failed provider=%s method=%s url=%s request_id=%s err=%v", provider, request.Method, urlStr, requestID, err)
 		}
 		return nil, fmt.Errorf("provider request failed: %w", err)
 	}
@@ -152,7 +155,7 @@ func (s *RequestService) ExecuteRequest(request config.PollingProviderRequestCon
 
 	responseBody, _ := io.ReadAll(resp.Body)
 	if s.logRequests {
-		log.Printf("polling: provider request provider=%s method=%s url=%s status=%d duration=%s", provider, request.Method, urlStr, resp.StatusCode, time.Since(start))
+		log.Printf("polling: provider request provider=%s method=%s url=%s status=%d duration=%s request_id=%s", provider, request.Method, urlStr, resp.StatusCode, time.Since(start), requestID)
 	}
 	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
 		return nil, fmt.Errorf("provider request failed: status %d: %s", resp.StatusCode, string(responseBody))
@@ -132,7 +132,7 @@ func (s *SubscriptionService) applyFetchStep(userID int, provider string, fetch
 			queryOverrides[fetch.Pagination.RequestParam] = pageToken
 		}
 
-		responseBody, err := s.requestSvc.ExecuteRequest(request, provider, userID, ctx, queryOverrides)
+		responseBody, err := s.requestSvc.ExecuteRequest("", request, provider, userID, ctx, queryOverrides)
 		if err != nil {
 			return err
 		}
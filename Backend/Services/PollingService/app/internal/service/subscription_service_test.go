@@ -79,8 +79,8 @@ type MockRequestService struct {
 	mock.Mock
 }
 
-func (m *MockRequestService) ExecuteRequest(request config.PollingProviderRequestConfig, provider string, userID int, ctx utils.TemplateContext, queryOverrides map[string]string) ([]byte, error) {
-	args := m.Called(request, provider, userID, ctx, queryOverrides)
+func (m *MockRequestService) ExecuteRequest(requestID string, request config.PollingProviderRequestConfig, provider string, userID int, ctx utils.TemplateContext, queryOverrides map[string]string) ([]byte, error) {
+	args := m.Called(requestID, request, provider, userID, ctx, queryOverrides)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
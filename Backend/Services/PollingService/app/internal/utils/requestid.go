@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+func NewRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(buf)
+}
+
+func RequestIDOrNew(requestID string) string {
+	if trimmed := strings.TrimSpace(requestID); trimmed != "" {
+		return trimmed
+	}
+	return NewRequestID()
+}
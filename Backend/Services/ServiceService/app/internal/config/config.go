@@ -299,6 +299,8 @@ type FieldConfig struct {
 	DefaultValuer string                 `json:"default"`
 	Selection     []FieldSelectionOption `json:"selection,omitempty"`
 	Multiple      bool                   `json:"multiple,omitempty"`
+	// Secret fields hold credentials, left out when areas are exported.
+	Secret bool `json:"secret,omitempty"`
 }
 
 type FieldSelectionOption struct {
@@ -312,6 +314,14 @@ type OutputField struct {
 	Label string `json:"label"`
 }
 
+// ReactionOutputMapping extracts a field from the response of a reaction, so
+// the next reactions of the area can use it as {{reactions.<n>.<field_key>}}.
+type ReactionOutputMapping struct {
+	FieldKey string `json:"field_key"`
+	JSONPath string `json:"json_path"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
 type ActionConfig struct {
 	Title        string        `json:"title"`
 	Label        string        `json:"label"`
@@ -329,6 +339,7 @@ type ReactionConfig struct {
 	BodyType   string          `json:"bodyType"`
 	BodyStruct json.RawMessage `json:"body_struct"`
 	Headers    map[string]string `json:"headers,omitempty"`
+	OutputMappings []ReactionOutputMapping `json:"output_mappings,omitempty"`
 }
 
 type ServiceConfig struct {
@@ -20,7 +20,7 @@ func main() {
 	oauth2TokenSvc := service.NewOAuth2TokenService(cfg.AuthServiceURL, cfg.InternalSecret)
 	webhookSetupSvc := service.NewWebhookSetupService(oauth2TokenSvc)
 	subscriptionSvc := service.NewSubscriptionService(repo, providerConfigSvc, webhookSetupSvc)
-	authSvc := service.NewAuthService(cfg.AuthServiceURL)
+	authSvc := service.NewAuthService(cfg.AuthServiceURL, cfg.InternalSecret)
 	areaTriggerSvc := service.NewAreaTriggerService(cfg.AreaServiceURL, cfg.InternalSecret)
 	renewalSvc := service.NewSubscriptionRenewalService(repo, providerConfigSvc, webhookSetupSvc, cfg.PublicBaseURL)
 	go renewalSvc.Start()
@@ -232,12 +232,14 @@ func (h *WebhookHandler) HandleReceiveWebhook(w http.ResponseWriter, req *http.R
 
 	outputFields := buildOutputFields(providerConfig.Mappings, mapped)
 	if h.areaTriggerSvc != nil {
-		if err := h.areaTriggerSvc.Trigger(subscription.ActionID, outputFields); err != nil {
+		requestID := utils.RequestIDOrNew(req.Header.Get(utils.RequestIDHeader))
+		if err := h.areaTriggerSvc.Trigger(requestID, subscription.ActionID, outputFields); err != nil {
 			log.Printf(
-				"webhook dispatch failed: hook_id=%s action_id=%d provider=%s error=%v",
+				"webhook dispatch failed: hook_id=%s action_id=%d provider=%s request_id=%s error=%v",
 				subscription.HookID,
 				subscription.ActionID,
 				subscription.Service,
+				requestID,
 				err,
 			)
 		}
@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+
+	"github.com/raphael-guer1n/AREA/Shared/template"
 )
 
 type TemplateContext struct {
@@ -66,11 +68,7 @@ func RenderTemplateValue(value any, ctx TemplateContext) (any, error) {
 func RenderTemplateString(input string, ctx TemplateContext) (any, error) {
 	trimmed := strings.TrimSpace(input)
 	if matches := placeholderRegexp.FindStringSubmatch(trimmed); len(matches) == 2 && matches[0] == trimmed {
-		val, ok := resolvePlaceholder(matches[1], ctx)
-		if !ok {
-			return nil, MissingTemplateValueError{Key: matches[1]}
-		}
-		return val, nil
+		return renderPlaceholder(matches[1], ctx)
 	}
 
 	result := input
@@ -78,9 +76,9 @@ func RenderTemplateString(input string, ctx TemplateContext) (any, error) {
 		if len(match) != 2 {
 			continue
 		}
-		val, ok := resolvePlaceholder(match[1], ctx)
-		if !ok {
-			return nil, MissingTemplateValueError{Key: match[1]}
+		val, err := renderPlaceholder(match[1], ctx)
+		if err != nil {
+			return nil, err
 		}
 		switch val.(type) {
 		case map[string]any, []any:
@@ -93,6 +91,23 @@ func RenderTemplateString(input string, ctx TemplateContext) (any, error) {
 	return result, nil
 }
 
+// renderPlaceholder resolves the key of a placeholder and runs its filters.
+func renderPlaceholder(expr string, ctx TemplateContext) (any, error) {
+	parsed, err := template.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	val, ok := resolvePlaceholder(parsed.Key, ctx)
+	val, ok, err = parsed.Apply(val, ok)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, MissingTemplateValueError{Key: parsed.Key}
+	}
+	return val, nil
+}
+
 func resolvePlaceholder(key string, ctx TemplateContext) (any, bool) {
 	key = strings.TrimSpace(key)
 	switch key {
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/raphael-guer1n/AREA/Shared/template"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTemplateContext() TemplateContext {
+	return TemplateContext{
+		HookID:  "hook-1",
+		UserID:  42,
+		Method:  "POST",
+		Headers: http.Header{"X-Github-Event": []string{"issues"}},
+		Config: map[string]any{
+			"repo":   "area/backend",
+			"issue":  map[string]any{"title": "Crash on <b>save</b>", "labels": []any{"bug", "ui"}},
+			"number": float64(7),
+		},
+	}
+}
+
+func TestRenderTemplateString_Filters(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected any
+	}{
+		{"{{config.repo | upper}}", "AREA/BACKEND"},
+		{"{{ config.issue.title | html_strip | truncate:8 }}", "Crash..."},
+		{"{{config.issue.labels | join:\"/\"}}", "bug/ui"},
+		{"{{headers.X-Github-Event | upper}}", "ISSUES"},
+		{"event {{method | lower}} #{{config.number}}", "event post #7"},
+		{"{{config.issue.labels | json}}", `["bug","ui"]`},
+		{"{{config.number}}", float64(7)},
+		{"{{user_id}}", 42},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			rendered, err := RenderTemplateString(tc.input, testTemplateContext())
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, rendered)
+		})
+	}
+}
+
+func TestRenderTemplateString_Defaults(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected any
+	}{
+		{`{{config.assignee | default:"nobody"}}`, "nobody"},
+		{`{{config.assignee | upper | default:"nobody"}}`, "nobody"},
+		{`{{config.assignee | default:"nobody" | upper}}`, "NOBODY"},
+		{`{{config.repo | default:"none"}}`, "area/backend"},
+		{`by {{path | default:'/'}}`, "by /"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			rendered, err := RenderTemplateString(tc.input, testTemplateContext())
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, rendered)
+		})
+	}
+}
+
+func TestRenderTemplateString_Errors(t *testing.T) {
+	t.Run("missing value without default", func(t *testing.T) {
+		_, err := RenderTemplateString("{{config.assignee | upper}}", testTemplateContext())
+
+		var missing MissingTemplateValueError
+		assert.True(t, errors.As(err, &missing))
+		assert.Equal(t, "config.assignee", missing.Key)
+	})
+
+	t.Run("unknown filter", func(t *testing.T) {
+		_, err := RenderTemplateString("{{config.repo | shout}}", testTemplateContext())
+
+		assert.ErrorIs(t, err, template.ErrInvalid)
+	})
+
+	t.Run("filter failure", func(t *testing.T) {
+		_, err := RenderTemplateString("{{config.repo | date}}", testTemplateContext())
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,3 @@
+module github.com/raphael-guer1n/AREA/Shared
+
+go 1.22
@@ -0,0 +1,100 @@
+// Package identity reads the user identity that the gateway injects in the
+// requests it has authenticated, signed with the internal secret.
+package identity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Identity headers injected by the gateway once it has verified the JWT.
+const (
+	UserIDHeader          = "X-User-ID"
+	UserEmailHeader       = "X-User-Email"
+	UserPermissionsHeader = "X-User-Permissions"
+	UserTimestampHeader   = "X-User-Timestamp"
+	UserSignatureHeader   = "X-User-Signature"
+)
+
+// MaxAge is how far the signing time of the headers may be from now.
+const MaxAge = 5 * time.Minute
+
+var ErrNoIdentity = errors.New("no gateway identity headers")
+
+type Identity struct {
+	UserID      int
+	Email       string
+	Permissions []string
+}
+
+// FromRequest reads the gateway identity headers and checks their signature
+// against the internal secret. It returns ErrNoIdentity when the request did
+// not go through the gateway auth, so callers can fall back.
+func FromRequest(req *http.Request, internalSecret string) (*Identity, error) {
+	rawID := strings.TrimSpace(req.Header.Get(UserIDHeader))
+	if rawID == "" {
+		return nil, ErrNoIdentity
+	}
+	if internalSecret == "" {
+		return nil, errors.New("internal secret not configured")
+	}
+
+	email := req.Header.Get(UserEmailHeader)
+	permissions := req.Header.Get(UserPermissionsHeader)
+	timestamp := req.Header.Get(UserTimestampHeader)
+
+	expected := signature(internalSecret, rawID, email, permissions, timestamp)
+	if !hmac.Equal([]byte(expected), []byte(req.Header.Get(UserSignatureHeader))) {
+		return nil, errors.New("invalid identity signature")
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid identity timestamp")
+	}
+	if age := time.Since(time.Unix(ts, 0)); age > MaxAge || age < -MaxAge {
+		return nil, errors.New("identity headers expired")
+	}
+
+	userID, err := strconv.Atoi(rawID)
+	if err != nil || userID <= 0 {
+		return nil, errors.New("invalid user id")
+	}
+
+	identity := &Identity{
+		UserID: userID,
+		Email:  email,
+	}
+	for _, p := range strings.Split(permissions, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			identity.Permissions = append(identity.Permissions, p)
+		}
+	}
+	return identity, nil
+}
+
+// SetHeaders writes the identity headers signed at issuedAt, as the gateway
+// does.
+func SetHeaders(header http.Header, identity Identity, issuedAt time.Time, internalSecret string) {
+	userID := strconv.Itoa(identity.UserID)
+	permissions := strings.Join(identity.Permissions, ",")
+	timestamp := strconv.FormatInt(issuedAt.Unix(), 10)
+
+	header.Set(UserIDHeader, userID)
+	header.Set(UserEmailHeader, identity.Email)
+	header.Set(UserPermissionsHeader, permissions)
+	header.Set(UserTimestampHeader, timestamp)
+	header.Set(UserSignatureHeader, signature(internalSecret, userID, identity.Email, permissions, timestamp))
+}
+
+func signature(internalSecret, userID, email, permissions, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(internalSecret))
+	mac.Write([]byte(userID + "\n" + email + "\n" + permissions + "\n" + timestamp))
+	return hex.EncodeToString(mac.Sum(nil))
+}
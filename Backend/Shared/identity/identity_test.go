@@ -0,0 +1,111 @@
+package identity
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const testSecret = "internal-secret"
+
+func signedRequest(secret string, issuedAt time.Time) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	SetHeaders(req.Header, Identity{UserID: 42, Email: "user@example.com", Permissions: []string{"areas:read", "admin"}}, issuedAt, secret)
+	return req
+}
+
+func TestFromRequest_Valid(t *testing.T) {
+	identity, err := FromRequest(signedRequest(testSecret, time.Now()), testSecret)
+	if err != nil {
+		t.Fatalf("FromRequest: %v", err)
+	}
+
+	want := &Identity{UserID: 42, Email: "user@example.com", Permissions: []string{"areas:read", "admin"}}
+	if !reflect.DeepEqual(identity, want) {
+		t.Fatalf("identity = %+v, want %+v", identity, want)
+	}
+}
+
+func TestFromRequest_NoHeaders(t *testing.T) {
+	identity, err := FromRequest(httptest.NewRequest(http.MethodGet, "/", nil), testSecret)
+
+	if !errors.Is(err, ErrNoIdentity) || identity != nil {
+		t.Fatalf("FromRequest = %v, %v, want ErrNoIdentity", identity, err)
+	}
+}
+
+func TestFromRequest_Rejected(t *testing.T) {
+	testCases := []struct {
+		name     string
+		request  func() *http.Request
+		noSecret bool
+	}{
+		{
+			name:    "signed with another secret",
+			request: func() *http.Request { return signedRequest("forged-secret", time.Now()) },
+		},
+		{
+			name: "user id changed after signing",
+			request: func() *http.Request {
+				req := signedRequest(testSecret, time.Now())
+				req.Header.Set(UserIDHeader, "1")
+				return req
+			},
+		},
+		{
+			name: "permissions changed after signing",
+			request: func() *http.Request {
+				req := signedRequest(testSecret, time.Now())
+				req.Header.Set(UserPermissionsHeader, "admin,root")
+				return req
+			},
+		},
+		{
+			name: "unsigned",
+			request: func() *http.Request {
+				req := signedRequest(testSecret, time.Now())
+				req.Header.Del(UserSignatureHeader)
+				return req
+			},
+		},
+		{
+			name:    "expired",
+			request: func() *http.Request { return signedRequest(testSecret, time.Now().Add(-MaxAge-time.Minute)) },
+		},
+		{
+			name:    "issued in the future",
+			request: func() *http.Request { return signedRequest(testSecret, time.Now().Add(MaxAge+time.Minute)) },
+		},
+		{
+			name: "invalid user id",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				SetHeaders(req.Header, Identity{UserID: 0}, time.Now(), testSecret)
+				return req
+			},
+		},
+		{
+			name:     "internal secret not configured",
+			request:  func() *http.Request { return signedRequest("", time.Now()) },
+			noSecret: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			secret := testSecret
+			if tc.noSecret {
+				secret = ""
+			}
+
+			identity, err := FromRequest(tc.request(), secret)
+
+			if err == nil || errors.Is(err, ErrNoIdentity) || identity != nil {
+				t.Fatalf("FromRequest = %v, %v, want a rejection", identity, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,346 @@
+// Package template parses the placeholders of the templates shared by the
+// AreaService reaction inputs and the PollingService and WebhookService
+// configs. Filters follow the key of a placeholder, each after a pipe, with
+// their arguments after a colon:
+//
+//	{{ title | upper | truncate:2000 }}
+//	{{ pub_date | date:"02/01/2006 15:04","Europe/Paris" | default:"unknown" }}
+package template
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // the runtime image ships without a zoneinfo database
+	"unicode/utf8"
+)
+
+var ErrInvalid = errors.New("invalid template")
+
+type templateFilter struct {
+	name string
+	args []string
+}
+
+// Expression is a parsed placeholder: the key of its value and its filters.
+type Expression struct {
+	Key     string
+	filters []templateFilter
+}
+
+type templateFilterSpec struct {
+	minArgs int
+	maxArgs int
+	check   func(args []string) error
+	apply   func(value any, args []string) (any, error)
+}
+
+// templateFilters lists the filters. default has no apply function: it is the
+// only filter to run on a missing value, see Expression.Apply.
+var templateFilters = map[string]templateFilterSpec{
+	"default": {minArgs: 1, maxArgs: 1},
+	"upper": {apply: func(value any, _ []string) (any, error) {
+		return strings.ToUpper(String(value)), nil
+	}},
+	"lower": {apply: func(value any, _ []string) (any, error) {
+		return strings.ToLower(String(value)), nil
+	}},
+	"trim": {apply: func(value any, _ []string) (any, error) {
+		return strings.TrimSpace(String(value)), nil
+	}},
+	"truncate": {minArgs: 1, maxArgs: 2, check: checkTruncateArgs, apply: truncateFilter},
+	"replace": {minArgs: 2, maxArgs: 2, apply: func(value any, args []string) (any, error) {
+		return strings.ReplaceAll(String(value), args[0], args[1]), nil
+	}},
+	"date": {maxArgs: 2, check: checkDateArgs, apply: dateFilter},
+	"json": {apply: func(value any, _ []string) (any, error) {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		return string(encoded), nil
+	}},
+	"urlencode": {apply: func(value any, _ []string) (any, error) {
+		return url.QueryEscape(String(value)), nil
+	}},
+	"html_strip": {apply: func(value any, _ []string) (any, error) {
+		stripped := htmlTagRegexp.ReplaceAllString(String(value), "")
+		return strings.TrimSpace(html.UnescapeString(stripped)), nil
+	}},
+	"join": {maxArgs: 1, apply: func(value any, args []string) (any, error) {
+		items, ok := value.([]any)
+		if !ok {
+			return String(value), nil
+		}
+		separator := ", "
+		if len(args) == 1 {
+			separator = args[0]
+		}
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = String(item)
+		}
+		return strings.Join(parts, separator), nil
+	}},
+}
+
+var htmlTagRegexp = regexp.MustCompile(`<[^>]*>`)
+
+// dateLayouts are the formats date reads, unix timestamps aside.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC822Z,
+	time.RFC822,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// dateLayoutNames are shortcuts for the usual date output layouts. Any other
+// layout is a Go reference layout.
+var dateLayoutNames = map[string]string{
+	"rfc3339":  time.RFC3339,
+	"rfc1123":  time.RFC1123Z,
+	"date":     "2006-01-02",
+	"time":     "15:04",
+	"datetime": "2006-01-02 15:04",
+}
+
+// Parse reads the content of a placeholder, between the braces. It fails with
+// ErrInvalid on an unknown filter or on wrong filter arguments.
+func Parse(expr string) (Expression, error) {
+	parts, err := splitTemplateArgs(expr, '|')
+	if err != nil {
+		return Expression{}, err
+	}
+	parsed := Expression{Key: strings.TrimSpace(parts[0])}
+	if parsed.Key == "" {
+		return Expression{}, fmt.Errorf("%w: missing key in {{%s}}", ErrInvalid, expr)
+	}
+
+	for _, part := range parts[1:] {
+		name, rawArgs, hasArgs := strings.Cut(strings.TrimSpace(part), ":")
+		filter := templateFilter{name: strings.TrimSpace(name)}
+		spec, ok := templateFilters[filter.name]
+		if !ok {
+			return Expression{}, fmt.Errorf("%w: unknown filter %q", ErrInvalid, filter.name)
+		}
+		if hasArgs {
+			args, err := splitTemplateArgs(rawArgs, ',')
+			if err != nil {
+				return Expression{}, err
+			}
+			for _, arg := range args {
+				value, err := unquoteTemplateArg(strings.TrimSpace(arg))
+				if err != nil {
+					return Expression{}, err
+				}
+				filter.args = append(filter.args, value)
+			}
+		}
+		if len(filter.args) < spec.minArgs || len(filter.args) > spec.maxArgs {
+			return Expression{}, fmt.Errorf("%w: filter %s takes %d to %d arguments", ErrInvalid, filter.name, spec.minArgs, spec.maxArgs)
+		}
+		if spec.check != nil {
+			if err := spec.check(filter.args); err != nil {
+				return Expression{}, fmt.Errorf("%w: filter %s: %v", ErrInvalid, filter.name, err)
+			}
+		}
+		parsed.filters = append(parsed.filters, filter)
+	}
+	return parsed, nil
+}
+
+// Apply runs the filters on the value of the key. found tells whether the key
+// has a value: until a default filter provides one, the other filters are
+// skipped. The returned flag is false when the value is still missing.
+func (e Expression) Apply(value any, found bool) (any, bool, error) {
+	for _, filter := range e.filters {
+		if filter.name == "default" {
+			if !found || value == nil || value == "" {
+				value, found = filter.args[0], true
+			}
+			continue
+		}
+		if !found {
+			continue
+		}
+		var err error
+		value, err = templateFilters[filter.name].apply(value, filter.args)
+		if err != nil {
+			return nil, false, fmt.Errorf("filter %s on %s: %w", filter.name, e.Key, err)
+		}
+	}
+	return value, found, nil
+}
+
+// splitTemplateArgs splits s on sep, except inside quotes.
+func splitTemplateArgs(s string, sep rune) ([]string, error) {
+	var parts []string
+	var quote rune
+	escaped := false
+	start := 0
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0 && r == '\\':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == sep:
+			parts = append(parts, s[start:i])
+			start = i + utf8.RuneLen(r)
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("%w: unterminated quote in %q", ErrInvalid, s)
+	}
+	return append(parts, s[start:]), nil
+}
+
+func unquoteTemplateArg(arg string) (string, error) {
+	if len(arg) >= 2 && arg[0] == '"' && arg[len(arg)-1] == '"' {
+		value, err := strconv.Unquote(arg)
+		if err != nil {
+			return "", fmt.Errorf("%w: argument %s: %v", ErrInvalid, arg, err)
+		}
+		return value, nil
+	}
+	if len(arg) >= 2 && arg[0] == '\'' && arg[len(arg)-1] == '\'' {
+		return strings.ReplaceAll(arg[1:len(arg)-1], `\'`, `'`), nil
+	}
+	return arg, nil
+}
+
+// String is the text of a value once embedded in a string.
+func String(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	case map[string]any, []any:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(encoded)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func checkTruncateArgs(args []string) error {
+	if length, err := strconv.Atoi(args[0]); err != nil || length < 0 {
+		return fmt.Errorf("length %q is not a positive number", args[0])
+	}
+	return nil
+}
+
+// truncateFilter cuts the value to a number of characters, the suffix
+// ("..." by default) included.
+func truncateFilter(value any, args []string) (any, error) {
+	length, err := strconv.Atoi(args[0])
+	if err != nil {
+		return nil, err
+	}
+	suffix := "..."
+	if len(args) == 2 {
+		suffix = args[1]
+	}
+
+	text := []rune(String(value))
+	if len(text) <= length {
+		return string(text), nil
+	}
+	keep := length - utf8.RuneCountInString(suffix)
+	if keep < 0 {
+		return string(text[:length]), nil
+	}
+	return string(text[:keep]) + suffix, nil
+}
+
+func checkDateArgs(args []string) error {
+	if len(args) == 2 {
+		if _, err := time.LoadLocation(args[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dateFilter formats a date with a layout (RFC 3339 by default) in a time zone
+// (the one of the date by default). The layout unix gives a timestamp.
+func dateFilter(value any, args []string) (any, error) {
+	date, err := parseTemplateDate(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 2 {
+		location, err := time.LoadLocation(args[1])
+		if err != nil {
+			return nil, err
+		}
+		date = date.In(location)
+	}
+
+	layout := time.RFC3339
+	if len(args) >= 1 && args[0] != "" {
+		layout = args[0]
+	}
+	if layout == "unix" {
+		return strconv.FormatInt(date.Unix(), 10), nil
+	}
+	if named, ok := dateLayoutNames[layout]; ok {
+		layout = named
+	}
+	return date.Format(layout), nil
+}
+
+func parseTemplateDate(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case float64:
+		return unixTemplateDate(int64(v)), nil
+	case int:
+		return unixTemplateDate(int64(v)), nil
+	case int64:
+		return unixTemplateDate(v), nil
+	case time.Time:
+		return v, nil
+	}
+
+	text := strings.TrimSpace(String(value))
+	if timestamp, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return unixTemplateDate(timestamp), nil
+	}
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, text); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date", text)
+}
+
+// unixTemplateDate reads a timestamp in seconds, or in milliseconds when it
+// is too large to be in seconds.
+func unixTemplateDate(timestamp int64) time.Time {
+	if timestamp > 1e12 || timestamp < -1e12 {
+		return time.UnixMilli(timestamp).UTC()
+	}
+	return time.Unix(timestamp, 0).UTC()
+}
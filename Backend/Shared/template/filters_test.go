@@ -0,0 +1,121 @@
+package template
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExpression_Apply(t *testing.T) {
+	testCases := []struct {
+		expr     string
+		value    any
+		found    bool
+		expected any
+	}{
+		{"title", "Release v2.0", true, "Release v2.0"},
+		{"title | upper", "Release v2.0", true, "RELEASE V2.0"},
+		{"title|lower", "Release v2.0", true, "release v2.0"},
+		{"title | trim", "  padded  ", true, "padded"},
+		{"title | truncate:7", "Release v2.0", true, "Rele..."},
+		{`title | truncate:7,""`, "Release v2.0", true, "Release"},
+		{"title | truncate:3", strings.Repeat("é", 5), true, "..."},
+		{`title | replace:"v","version "`, "Release v2.0", true, "Release version 2.0"},
+		{"description | html_strip", "<p>Fixes &amp; improvements</p>", true, "Fixes & improvements"},
+		{"pub_date | date", "Mon, 02 Jan 2006 15:04:05 +0000", true, "2006-01-02T15:04:05Z"},
+		{`pub_date | date:"02/01/2006 15:04","Europe/Paris"`, "Mon, 02 Jan 2006 15:04:05 +0000", true, "02/01/2006 16:04"},
+		{"timestamp | date:date", float64(1136214245), true, "2006-01-02"},
+		{"timestamp | date:time", int64(1136214245000), true, "15:04"},
+		{"pub_date | date:unix", "2006-01-02T15:04:05Z", true, "1136214245"},
+		{"labels | join", []any{"bug", "ui"}, true, "bug, ui"},
+		{`labels | join:"/"`, []any{"bug", "ui"}, true, "bug/ui"},
+		{"item | json", map[string]any{"id": 1}, true, `{"id":1}`},
+		{"query | urlencode", "a b&c", true, "a+b%26c"},
+		{"empty | default:'none'", "", true, "none"},
+		{"count | default:'none'", 0, true, 0},
+		{`missing | upper | default:"n/a"`, nil, false, "n/a"},
+		{`missing | default:"n/a" | upper`, nil, false, "N/A"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			expression, err := Parse(tc.expr)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			value, found, err := expression.Apply(tc.value, tc.found)
+			if err != nil {
+				t.Fatalf("apply: %v", err)
+			}
+			if !found {
+				t.Fatalf("value still missing")
+			}
+			if value != tc.expected {
+				t.Fatalf("got %#v, want %#v", value, tc.expected)
+			}
+		})
+	}
+}
+
+func TestExpression_ApplyMissingValue(t *testing.T) {
+	expression, err := Parse("missing | upper")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	value, found, err := expression.Apply(nil, false)
+
+	if err != nil || found || value != nil {
+		t.Fatalf("Apply = %v, %v, %v, want a missing value", value, found, err)
+	}
+}
+
+func TestExpression_ApplyFilterError(t *testing.T) {
+	expression, err := Parse("pub_date | date")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	if _, _, err := expression.Apply("not a date", true); err == nil {
+		t.Fatal("date filter accepted a value that is not a date")
+	}
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		expr  string
+		key   string
+		valid bool
+	}{
+		{" title ", "title", true},
+		{"description | html_strip | truncate:2000", "description", true},
+		{`pub_date | date:"15:04","Europe/Paris"`, "pub_date", true},
+		{`config.name | default:"a | b"`, "config.name", true},
+		{"", "", false},
+		{"| upper", "", false},
+		{"title | shout", "", false},
+		{"title | truncate", "", false},
+		{"title | truncate:many", "", false},
+		{"title | upper:1", "", false},
+		{`pub_date | date:"15:04","Mars/Olympus"`, "", false},
+		{`title | default:"open`, "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			expression, err := Parse(tc.expr)
+			if !tc.valid {
+				if !errors.Is(err, ErrInvalid) {
+					t.Fatalf("error = %v, want ErrInvalid", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if expression.Key != tc.key {
+				t.Fatalf("Key = %q, want %q", expression.Key, tc.key)
+			}
+		})
+	}
+}